@@ -2,114 +2,524 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi"
-	"github.com/go-chi/chi/middleware"
 	"github.com/mfritschdotgo/techchallenge/configs"
-	_ "github.com/mfritschdotgo/techchallenge/docs"
+	docsv1 "github.com/mfritschdotgo/techchallenge/docs/v1"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/event"
 	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/httpserver"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/metrics"
 	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/migration"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/postgres"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlite"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlstore"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/tracing"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
 	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
-// @title			Skina Lanches Management API
-// @version		1.0
-// @description	APIs for using the management system and sales orders
-// @BasePath					/
+// legacyRoutesDeprecated is when the unversioned routes were superseded by /v1.
+var legacyRoutesDeprecated = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
 func main() {
-	config := configs.GetConfig()
+	printConfig := flag.Bool("print-config", false, "print the effective configuration, with secrets masked, and exit")
+	config, err := configs.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *printConfig {
+		if err := config.Print(os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
 
-	client, err := connectDatabase(config.MONGO_USER, config.MONGO_PASSWORD, config.MONGO_HOST, config.MONGO_PORT, config.MONGO_DATABASE)
+	logger, err := logging.New(os.Stdout, config.LOG_LEVEL)
 	if err != nil {
 		panic(err)
 	}
-	db := client.Database(config.MONGO_DATABASE)
+	// The standard log package, used by some dependencies, writes through logger too.
+	slog.SetDefault(logger)
+
+	// ctx is cancelled on SIGINT or SIGTERM, which starts the shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, config.OTEL_TRACES_EXPORTER, config.OTEL_SERVICE_NAME)
+	if err != nil {
+		panic(err)
+	}
+
+	var repos *repositories
+	switch config.DB_DRIVER {
+	case "mongo":
+		monitor := repository.NewConnectionMonitor()
+		client, err := connectDatabase(ctx, config, monitor)
+		if err != nil {
+			panic(err)
+		}
+		db := client.Database(mongoDatabase(config))
+
+		if flag.Arg(0) == "migrate" {
+			if err := migrateCommand(flag.Args()[1:], mongoMigrations(db)); err != nil {
+				logger.Error("migration failed", "error", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		repos, err = newMongoRepositories(ctx, client, db, monitor, config.DB_OPERATION_TIMEOUT)
+		if err != nil {
+			panic(err)
+		}
+	case "postgres", "sqlite":
+		db, err := openSQL(ctx, config)
+		if err != nil {
+			panic(err)
+		}
+
+		if flag.Arg(0) == "migrate" {
+			if err := migrateCommand(flag.Args()[1:], sqlMigrations(db)); err != nil {
+				logger.Error("migration failed", "error", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		repos, err = newSQLRepositories(ctx, db, config.DB_OPERATION_TIMEOUT)
+		if err != nil {
+			panic(err)
+		}
+		if config.DB_DRIVER == "sqlite" {
+			repos.backup = sqlite.NewOnlineBackup(db)
+		}
+	case "memory":
+		logger.Warn("using in-memory repositories, data will be lost on exit")
+		repos = newMemoryRepositories()
+	default:
+		panic(fmt.Sprintf("unknown storage backend %q", config.DB_DRIVER))
+	}
 
-	categoryRepo := repository.NewCategoryRepository(db)
-	categoryService := service.NewCategoryService(categoryRepo)
-	categoryHandler := httpserver.NewCategoryHandler(categoryService)
+	// The orders by status gauge queries the bare repository so scrapes do not skew the repository timings.
+	apiMetrics := metrics.New(repos.orders)
+	repos.instrument(apiMetrics)
 
-	productRepo := repository.NewProductRepository(db)
-	productService := service.NewProductService(productRepo, categoryService)
+	categoryService := service.NewCategoryService(repos.categories)
+	tracedCategoryService := tracing.CategoryService(categoryService)
+	categoryHandler := httpserver.NewCategoryHandler(tracedCategoryService)
+
+	productService := tracing.ProductService(service.NewProductService(repos.products, repos.outbox, repos.uow, tracedCategoryService))
 	productHandler := httpserver.NewProductHandler(productService)
 
-	clientRepo := repository.NewClientRepository(db)
-	clientService := service.NewClientService(clientRepo)
+	clientService := tracing.ClientService(service.NewClientService(repos.clients))
 	clientHandler := httpserver.NewClientHandler(clientService)
 
-	orderRepo := repository.NewOrderRepository(db)
-	orderService := service.NewOrderService(orderRepo, clientService, productService)
+	orderService := tracing.OrderService(service.NewOrderService(repos.orders, repos.outbox, repos.uow, clientService, productService))
 	orderHandler := httpserver.NewOrderHandler(orderService)
 
-	err = categoryService.InitializeCategories(context.Background())
+	webhookService := service.NewWebhookService(repos.webhookSubscriptions, repos.webhookDeliveries, event.NewHTTPWebhookSender(nil))
+	webhookHandler := httpserver.NewWebhookHandler(tracing.WebhookService(webhookService))
+
+	err = categoryService.InitializeCategories(ctx)
 	if err != nil {
 		panic(err)
 	}
 
+	publisher, err := newEventPublisher(config)
+	if err != nil {
+		panic(err)
+	}
+
+	outboxRelay := service.NewOutboxRelay(repos.outbox, event.NewFanout(publisher, webhookService, apiMetrics), config.OUTBOX_RELAY_INTERVAL)
+
+	healthService := newHealthService(repos, outboxRelay, webhookService)
+	healthHandler := httpserver.NewHealthHandler(healthService)
+
+	// Workers get their own context so they keep running while in-flight requests drain.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		outboxRelay.Run(workersCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		webhookService.Run(workersCtx, config.WEBHOOK_DELIVERY_INTERVAL)
+	}()
+
 	r := chi.NewRouter()
 
 	// Middlewares
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-
-	r.Route("/products", func(r chi.Router) {
-		r.Post("/", productHandler.CreateProduct)
-		r.Put("/{id}", productHandler.ReplaceProduct)
-		r.Patch("/{id}", productHandler.UpdateProduct)
-		r.Get("/{id}", productHandler.GetProductByID)
-		r.Get("/", productHandler.GetProducts)
-		r.Delete("/{id}", productHandler.DeleteProduct)
-	})
-
-	r.Route("/categories", func(r chi.Router) {
-		r.Post("/", categoryHandler.CreateCategory)
-		r.Patch("/{id}", categoryHandler.UpdateCategory)
-		r.Put("/{id}", categoryHandler.ReplaceCategory)
-		r.Get("/{id}", categoryHandler.GetCategoryByID)
-		r.Get("/", categoryHandler.GetCategories)
-		r.Delete("/{id}", categoryHandler.DeleteCategory)
-	})
-
-	r.Route("/clients", func(r chi.Router) {
-		r.Post("/", clientHandler.CreateClient)
-		r.Get("/{cpf}", clientHandler.GetClientByCPF)
-	})
-
-	r.Route("/orders", func(r chi.Router) {
-		r.Get("/", orderHandler.GetOrders)
-		r.Get("/{id}", orderHandler.GetOrderByID)
-		r.Post("/", orderHandler.CreateOrder)
-		r.Patch("/{id}/{status}", orderHandler.SetOrderStatus)
-	})
-
-	r.Route("/fakeCheckout", func(r chi.Router) {
-		r.Post("/{id}", orderHandler.FakeCheckout)
-	})
-
-	r.Get("/docs/*", httpSwagger.Handler(httpSwagger.URL("/docs/doc.json")))
-
-	http.ListenAndServe(":9090", r)
+	r.Use(tracing.Middleware)
+	r.Use(apiMetrics.Middleware)
+	r.Use(httpserver.RequestID(logger))
+	r.Use(httpserver.AccessLog)
+	r.Use(httpserver.Recoverer)
+
+	r.Get("/healthz", healthHandler.Live)
+	r.Get("/readyz", healthHandler.Ready)
+	r.Handle("/metrics", apiMetrics.Handler())
+
+	handlers := httpserver.Handlers{
+		Categories: categoryHandler,
+		Products:   productHandler,
+		Clients:    clientHandler,
+		Orders:     orderHandler,
+		Webhooks:   webhookHandler,
+		AdminToken: config.ADMIN_TOKEN,
+	}
+	r.Route("/v1", handlers.V1)
+
+	// The unversioned routes predate /v1 and stay as deprecated aliases until the sunset date.
+	if config.LEGACY_ROUTES_ENABLED {
+		sunset, _ := time.Parse(time.DateOnly, config.LEGACY_ROUTES_SUNSET) // checked by Validate
+		r.Group(func(r chi.Router) {
+			r.Use(httpserver.Deprecated(legacyRoutesDeprecated, sunset, "/v1"))
+			handlers.V1(r)
+		})
+	}
+
+	// The backup holds every client CPF and e-mail and the webhook secrets, so it is only
+	// served to callers presenting BACKUP_TOKEN.
+	if repos.backup != nil && config.BACKUP_TOKEN != "" {
+		backupHandler := httpserver.NewBackupHandler(repos.backup)
+		r.With(httpserver.RequireBearerToken(config.BACKUP_TOKEN)).Get("/backup", backupHandler.Backup)
+	}
+
+	if config.SWAGGER_ENABLED {
+		r.Get("/docs/v1/*", httpSwagger.Handler(httpSwagger.InstanceName(docsv1.SwaggerInfov1.InstanceName()), httpSwagger.URL("/docs/v1/doc.json")))
+		r.Get("/docs", http.RedirectHandler("/docs/v1/index.html", http.StatusFound).ServeHTTP)
+		r.Get("/docs/*", http.RedirectHandler("/docs/v1/index.html", http.StatusFound).ServeHTTP)
+	}
+
+	server := &http.Server{
+		Addr:              ":" + config.HTTP_PORT,
+		Handler:           r,
+		ReadHeaderTimeout: config.HTTP_READ_TIMEOUT,
+		ReadTimeout:       config.HTTP_READ_TIMEOUT,
+		WriteTimeout:      config.HTTP_WRITE_TIMEOUT,
+		IdleTimeout:       config.HTTP_IDLE_TIMEOUT,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if config.HTTP_TLS_CERT_FILE != "" {
+			logger.Info("listening with TLS", "addr", server.Addr)
+			serverErr <- server.ListenAndServeTLS(config.HTTP_TLS_CERT_FILE, config.HTTP_TLS_KEY_FILE)
+			return
+		}
+		logger.Info("listening", "addr", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	var serveErr error
+	select {
+	case serveErr = <-serverErr:
+		logger.Error("http server stopped", "error", serveErr)
+	case <-ctx.Done():
+		// Fail readiness first and keep serving for a while, so load balancers
+		// stop sending new orders before the listener closes.
+		logger.Info("shutdown requested, failing readiness", "delay", config.SHUTDOWN_DELAY.String())
+		healthService.Drain()
+		select {
+		case serveErr = <-serverErr:
+			logger.Error("http server stopped", "error", serveErr)
+		case <-time.After(config.SHUTDOWN_DELAY):
+		}
+		logger.Info("draining in-flight requests")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	// Traces are flushed last so the spans of the shutdown itself are exported.
+	steps := append(shutdownSteps(server, stopWorkers, &workers, publisher, repos), shutdownStep{name: "traces", run: shutdownTracing})
+	shutdown(shutdownCtx, steps)
+
+	logger.Info("shutdown complete")
+	if serveErr != nil {
+		os.Exit(1)
+	}
+}
+
+// newHealthService registers the readiness checks of the storage backend and the background workers.
+func newHealthService(repos *repositories, relay *service.OutboxRelay, webhooks *service.WebhookService) *service.HealthService {
+	health := service.NewHealthService()
+
+	if repos.ping != nil {
+		health.Register("database", repos.ping)
+	}
+	if repos.pendingMigrations != nil {
+		health.Register("migrations", func(ctx context.Context) error {
+			pending, err := repos.pendingMigrations(ctx)
+			if err != nil {
+				return err
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d migrations pending", pending)
+			}
+			return nil
+		})
+	}
+	health.Register("outbox_relay", workerCheck(relay.Running))
+	health.Register("webhook_worker", workerCheck(webhooks.Running))
+
+	return health
+}
+
+func workerCheck(running func() bool) service.HealthCheck {
+	return func(context.Context) error {
+		if !running() {
+			return errors.New("not running")
+		}
+		return nil
+	}
 }
 
-func connectDatabase(user string, password string, host string, port string, dbname string) (*mongo.Client, error) {
+func newEventPublisher(config *configs.Configs) (port.EventPublisher, error) {
+	switch config.EVENT_PUBLISHER {
+	case "bus":
+		return event.NewBus(), nil
+	case "webhook":
+		if config.EVENT_WEBHOOK_URL == "" {
+			return nil, fmt.Errorf("EVENT_WEBHOOK_URL is required when EVENT_PUBLISHER is webhook")
+		}
+		return event.NewWebhookPublisher(config.EVENT_WEBHOOK_URL), nil
+	case "nats":
+		return event.NewNATSPublisher(config.NATS_URL, config.NATS_SUBJECT_PREFIX)
+	default:
+		return nil, fmt.Errorf("unknown EVENT_PUBLISHER %q", config.EVENT_PUBLISHER)
+	}
+}
 
-	uri := fmt.Sprintf("mongodb://%s:%s@%s:%s/%s?authSource=%s", user, password, host, port, dbname, user)
-	clientOptions := options.Client().ApplyURI(uri)
+// migrationStep is a migration listed or applied by the migrate subcommand.
+type migrationStep struct {
+	Version     int
+	Description string
+}
 
-	client, err := mongo.Connect(context.TODO(), clientOptions)
+// migrateCommand implements "skinaapis [-repo=mongo|postgres|sqlite] migrate [-dry-run]", applying or listing the pending migrations.
+func migrateCommand(args []string, migrate func(ctx context.Context, dryRun bool) ([]migrationStep, error)) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "list pending migrations without applying them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// When a migration fails, the ones applied before it are still listed.
+	migrations, err := migrate(context.Background(), *dryRun)
+	for _, m := range migrations {
+		if *dryRun {
+			slog.Info("pending migration", "version", m.Version, "description", m.Description)
+		} else {
+			slog.Info("applied migration", "version", m.Version, "description", m.Description)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(migrations) == 0 {
+		slog.Info("no pending migrations")
+	}
+	return nil
+}
+
+func mongoMigrations(db *mongo.Database) func(ctx context.Context, dryRun bool) ([]migrationStep, error) {
+	return func(ctx context.Context, dryRun bool) ([]migrationStep, error) {
+		migrations, err := migration.NewRunner(db, migration.All()).Run(ctx, dryRun)
+
+		steps := make([]migrationStep, 0, len(migrations))
+		for _, m := range migrations {
+			steps = append(steps, migrationStep{Version: m.Version, Description: m.Description})
+		}
+		return steps, err
+	}
+}
+
+func sqlMigrations(db *sqlstore.DB) func(ctx context.Context, dryRun bool) ([]migrationStep, error) {
+	return func(ctx context.Context, dryRun bool) ([]migrationStep, error) {
+		migrations, err := sqlstore.Migrate(ctx, db, dryRun)
+
+		steps := make([]migrationStep, 0, len(migrations))
+		for _, m := range migrations {
+			steps = append(steps, migrationStep{Version: m.Version, Description: m.Description})
+		}
+		return steps, err
+	}
+}
+
+// connectDatabase connects to MongoDB with MONGO_URI, or with a URI built from the other
+// MONGO_* settings. MongoDB may still be starting, e.g. under docker compose, so the first
+// ping is retried with backoff for up to MONGO_STARTUP_TIMEOUT.
+func connectDatabase(ctx context.Context, config *configs.Configs, monitor *repository.ConnectionMonitor) (*mongo.Client, error) {
+	readPreference, err := readpref.ModeFromString(config.MONGO_READ_PREFERENCE)
 	if err != nil {
 		return nil, err
 	}
+	readPref, err := readpref.New(readPreference)
+	if err != nil {
+		return nil, err
+	}
+
+	writeConcern := writeconcern.Majority()
+	if config.MONGO_WRITE_CONCERN != "majority" {
+		w, _ := strconv.Atoi(config.MONGO_WRITE_CONCERN)
+		writeConcern = &writeconcern.WriteConcern{W: w}
+	}
+
+	// The command monitor adds a span per MongoDB command, with the collection and operation as
+	// attributes; the server monitor follows the connection state for readiness.
+	clientOptions := options.Client().
+		ApplyURI(mongoURI(config)).
+		SetRegistry(repository.NewRegistry()).
+		SetMonitor(otelmongo.NewMonitor()).
+		SetServerMonitor(monitor.ServerMonitor()).
+		SetConnectTimeout(config.MONGO_CONNECT_TIMEOUT).
+		SetServerSelectionTimeout(config.MONGO_SERVER_SELECTION_TIMEOUT).
+		SetMaxPoolSize(config.MONGO_MAX_POOL_SIZE).
+		SetMinPoolSize(config.MONGO_MIN_POOL_SIZE).
+		SetReadPreference(readPref).
+		SetWriteConcern(writeConcern)
+
+	if config.MONGO_TLS {
+		tlsConfig, err := mongoTLSConfig(config.MONGO_TLS_CA_FILE)
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	startupCtx, cancel := context.WithTimeout(ctx, config.MONGO_STARTUP_TIMEOUT)
+	defer cancel()
+
+	delay := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err = client.Ping(startupCtx, readpref.Primary())
+		if err == nil {
+			return client, nil
+		}
+
+		slog.Warn("MongoDB is not reachable yet, retrying", "attempt", attempt, "retry_in", delay.String(), "error", err)
+		select {
+		case <-startupCtx.Done():
+			client.Disconnect(context.Background())
+			return nil, fmt.Errorf("MongoDB not reachable within MONGO_STARTUP_TIMEOUT (%s): %w", config.MONGO_STARTUP_TIMEOUT, err)
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, 10*time.Second)
+	}
+}
+
+func mongoURI(config *configs.Configs) string {
+	if config.MONGO_URI != "" {
+		return config.MONGO_URI
+	}
+
+	authSource := config.MONGO_AUTH_SOURCE
+	if authSource == "" {
+		authSource = config.MONGO_USER
+	}
+	uri := url.URL{
+		Scheme:   "mongodb",
+		Host:     net.JoinHostPort(config.MONGO_HOST, config.MONGO_PORT),
+		Path:     config.MONGO_DATABASE,
+		RawQuery: url.Values{"authSource": {authSource}}.Encode(),
+	}
+	if config.MONGO_USER != "" {
+		uri.User = url.UserPassword(config.MONGO_USER, config.MONGO_PASSWORD)
+	}
+	return uri.String()
+}
+
+// mongoDatabase is MONGO_DATABASE, or the database named in the path of MONGO_URI.
+func mongoDatabase(config *configs.Configs) string {
+	if config.MONGO_DATABASE != "" {
+		return config.MONGO_DATABASE
+	}
+	uri, err := url.Parse(config.MONGO_URI)
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(uri.Path, "/")
+}
+
+// mongoTLSConfig trusts the system roots plus the PEM certificates in caFile, if any.
+func mongoTLSConfig(caFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading MONGO_TLS_CA_FILE: %w", err)
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("MONGO_TLS_CA_FILE %s contains no PEM certificates", caFile)
+	}
+	tlsConfig.RootCAs = roots
+	return tlsConfig, nil
+}
 
-	err = client.Ping(context.TODO(), nil)
+// openSQL connects to the PostgreSQL server or opens the SQLite file described by config.
+func openSQL(ctx context.Context, config *configs.Configs) (*sqlstore.DB, error) {
+	if config.DB_DRIVER == "sqlite" {
+		return sqlite.Open(ctx, config.SQLITE_PATH)
+	}
+
+	db, err := postgres.Open(ctx, postgresDSN(config))
 	if err != nil {
 		return nil, err
 	}
+	db.SetMaxOpenConns(config.POSTGRES_MAX_OPEN_CONNS)
+	db.SetMaxIdleConns(config.POSTGRES_MAX_IDLE_CONNS)
+	db.SetConnMaxLifetime(config.POSTGRES_CONN_MAX_LIFETIME)
+	return db, nil
+}
 
-	return client, nil
+func postgresDSN(config *configs.Configs) string {
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(config.POSTGRES_USER, config.POSTGRES_PASSWORD),
+		Host:     net.JoinHostPort(config.POSTGRES_HOST, config.POSTGRES_PORT),
+		Path:     config.POSTGRES_DATABASE,
+		RawQuery: url.Values{"sslmode": {config.POSTGRES_SSLMODE}}.Encode(),
+	}
+	return dsn.String()
 }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/metrics"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/memory"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/migration"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlstore"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// repositories holds the storage adapters selected with DB_DRIVER or the -repo flag.
+type repositories struct {
+	categories           port.CategoryRepository
+	products             port.ProductRepository
+	clients              port.ClientRepository
+	orders               port.OrderRepository
+	outbox               port.OutboxRepository
+	webhookSubscriptions port.WebhookSubscriptionRepository
+	webhookDeliveries    port.WebhookDeliveryRepository
+	uow                  port.UnitOfWork
+	// backup is only set for storage that can be snapshotted by the API itself.
+	backup port.DatabaseBackup
+	// ping and pendingMigrations back the readiness checks; both are nil for in-memory storage.
+	ping              func(ctx context.Context) error
+	pendingMigrations func(ctx context.Context) (int, error)
+	// disconnect releases the database connections once the API has drained.
+	disconnect func(ctx context.Context) error
+}
+
+func (r *repositories) close(ctx context.Context) error {
+	if r.disconnect == nil {
+		return nil
+	}
+	return r.disconnect(ctx)
+}
+
+// instrument wraps the repositories so every call is timed in m.
+func (r *repositories) instrument(m *metrics.Metrics) {
+	r.categories = m.InstrumentCategories(r.categories)
+	r.products = m.InstrumentProducts(r.products)
+	r.clients = m.InstrumentClients(r.clients)
+	r.orders = m.InstrumentOrders(r.orders)
+	r.outbox = m.InstrumentOutbox(r.outbox)
+	r.webhookSubscriptions = m.InstrumentWebhookSubscriptions(r.webhookSubscriptions)
+	r.webhookDeliveries = m.InstrumentWebhookDeliveries(r.webhookDeliveries)
+}
+
+// newMongoRepositories applies pending migrations and indexes before returning the MongoDB repositories.
+func newMongoRepositories(ctx context.Context, client *mongo.Client, db *mongo.Database, monitor *repository.ConnectionMonitor, timeout time.Duration) (*repositories, error) {
+	// Without transactions a failed order could leave stock, sales or events half written.
+	uow, err := repository.NewUnitOfWork(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if !uow.Transactional() {
+		return nil, errors.New("MongoDB is not running as a replica set, which the API needs for transactions; a single node replica set is enough")
+	}
+
+	if _, err := migration.NewRunner(db, migration.All()).Run(ctx, false); err != nil {
+		return nil, err
+	}
+
+	categoryRepo := repository.NewCategoryRepository(db, timeout)
+	productRepo := repository.NewProductRepository(db, timeout)
+	clientRepo := repository.NewClientRepository(db, timeout)
+	orderRepo := repository.NewOrderRepository(db, timeout)
+	outboxRepo := repository.NewOutboxRepository(db, timeout)
+	webhookSubscriptionRepo := repository.NewWebhookSubscriptionRepository(db, timeout)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db, timeout)
+
+	err = repository.EnsureIndexes(ctx, categoryRepo, productRepo, clientRepo, orderRepo, outboxRepo, webhookSubscriptionRepo, webhookDeliveryRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repositories{
+		categories:           categoryRepo,
+		products:             productRepo,
+		clients:              clientRepo,
+		orders:               orderRepo,
+		outbox:               outboxRepo,
+		webhookSubscriptions: webhookSubscriptionRepo,
+		webhookDeliveries:    webhookDeliveryRepo,
+		uow:                  uow,
+		// The monitor answers right away while the driver is reconnecting; the ping catches
+		// a server that is reachable but not answering.
+		ping: func(ctx context.Context) error {
+			if err := monitor.Check(ctx); err != nil {
+				return err
+			}
+			return client.Ping(ctx, readpref.Primary())
+		},
+		pendingMigrations: func(ctx context.Context) (int, error) {
+			pending, err := migration.NewRunner(db, migration.All()).Pending(ctx)
+			return len(pending), err
+		},
+		disconnect: client.Disconnect,
+	}, nil
+}
+
+// newSQLRepositories applies pending migrations before returning the PostgreSQL or SQLite repositories.
+func newSQLRepositories(ctx context.Context, db *sqlstore.DB, timeout time.Duration) (*repositories, error) {
+	if _, err := sqlstore.Migrate(ctx, db, false); err != nil {
+		return nil, err
+	}
+
+	return &repositories{
+		categories:           sqlstore.NewCategoryRepository(db, timeout),
+		products:             sqlstore.NewProductRepository(db, timeout),
+		clients:              sqlstore.NewClientRepository(db, timeout),
+		orders:               sqlstore.NewOrderRepository(db, timeout),
+		outbox:               sqlstore.NewOutboxRepository(db, timeout),
+		webhookSubscriptions: sqlstore.NewWebhookSubscriptionRepository(db, timeout),
+		webhookDeliveries:    sqlstore.NewWebhookDeliveryRepository(db, timeout),
+		uow:                  sqlstore.NewUnitOfWork(db),
+		ping:                 db.PingContext,
+		pendingMigrations: func(ctx context.Context) (int, error) {
+			pending, err := sqlstore.Pending(ctx, db)
+			return len(pending), err
+		},
+		disconnect: func(context.Context) error {
+			return db.Close()
+		},
+	}, nil
+}
+
+// newMemoryRepositories returns empty in-memory repositories; nothing is persisted across restarts.
+func newMemoryRepositories() *repositories {
+	categoryRepo := memory.NewCategoryRepository()
+	productRepo := memory.NewProductRepository()
+	clientRepo := memory.NewClientRepository()
+	orderRepo := memory.NewOrderRepository()
+	outboxRepo := memory.NewOutboxRepository()
+	webhookSubscriptionRepo := memory.NewWebhookSubscriptionRepository()
+	webhookDeliveryRepo := memory.NewWebhookDeliveryRepository()
+
+	return &repositories{
+		categories:           categoryRepo,
+		products:             productRepo,
+		clients:              clientRepo,
+		orders:               orderRepo,
+		outbox:               outboxRepo,
+		webhookSubscriptions: webhookSubscriptionRepo,
+		webhookDeliveries:    webhookDeliveryRepo,
+		uow:                  memory.NewUnitOfWork(),
+	}
+}
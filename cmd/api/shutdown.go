@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+// shutdownStep is one stage of the graceful shutdown.
+type shutdownStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// shutdownSteps returns the stages in the order they must run: in-flight requests are
+// drained first, then the background workers finish their batch, and only then are the
+// event publisher and the database, which both of them use, released.
+func shutdownSteps(server *http.Server, stopWorkers context.CancelFunc, workers *sync.WaitGroup, publisher port.EventPublisher, repos *repositories) []shutdownStep {
+	return []shutdownStep{
+		{name: "http server", run: server.Shutdown},
+		{name: "background workers", run: func(ctx context.Context) error {
+			stopWorkers()
+			return wait(ctx, workers)
+		}},
+		{name: "event publisher", run: func(context.Context) error {
+			if closer, ok := publisher.(interface{ Close() }); ok {
+				closer.Close()
+			}
+			return nil
+		}},
+		{name: "database", run: repos.close},
+	}
+}
+
+// shutdown runs steps in turn, logging the ones that fail and carrying on with the rest.
+func shutdown(ctx context.Context, steps []shutdownStep) {
+	for _, step := range steps {
+		if err := step.run(ctx); err != nil {
+			slog.Error("shutdown step failed", "step", step.name, "error", err)
+		}
+	}
+}
+
+// wait returns when wg is done or ctx expires, whichever comes first.
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// recorder collects what happened during the shutdown, in order.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+type closingPublisher struct {
+	events *recorder
+}
+
+func (p closingPublisher) Publish(context.Context, domain.Event) error { return nil }
+
+func (p closingPublisher) Close() { p.events.record("publisher closed") }
+
+func TestShutdownDrainsRequestsBeforeStoppingWorkersAndClosingStorage(t *testing.T) {
+	events := &recorder{}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		events.record("request finished")
+	})}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(listener)
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		<-workersCtx.Done()
+		events.record("worker stopped")
+	}()
+
+	repos := &repositories{disconnect: func(context.Context) error {
+		events.record("database closed")
+		return nil
+	}}
+
+	response := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err == nil {
+			resp.Body.Close()
+		}
+		response <- err
+	}()
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		shutdown(context.Background(), shutdownSteps(server, stopWorkers, &workers, closingPublisher{events}, repos))
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if got := events.recorded(); len(got) != 0 {
+		t.Fatalf("%v happened while a request was in flight", got)
+	}
+	close(release)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not finish")
+	}
+	if err := <-response; err != nil {
+		t.Fatalf("in-flight request failed: %v", err)
+	}
+
+	want := []string{"request finished", "worker stopped", "publisher closed", "database closed"}
+	if got := events.recorded(); !reflect.DeepEqual(got, want) {
+		t.Errorf("shutdown order %v, want %v", got, want)
+	}
+}
+
+func TestShutdownGivesUpOnWorkersAtTheDeadline(t *testing.T) {
+	var workers sync.WaitGroup
+	workers.Add(1)
+	defer workers.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := wait(ctx, &workers); err != context.DeadlineExceeded {
+		t.Fatalf("wait = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -1,23 +1,119 @@
 package configs
 
-import "os"
+import "time"
 
+// Configs is the effective configuration of the API. Every field can be set, from lowest to
+// highest precedence, by its default, the config file, the environment variable of the same
+// name, and for some fields a command line flag. See Load.
 type Configs struct {
+	// DB_DRIVER selects the storage backend: mongo (default), postgres, sqlite or memory.
+	DB_DRIVER string `mapstructure:"DB_DRIVER"`
+	// DB_OPERATION_TIMEOUT bounds each repository call on MongoDB, PostgreSQL and SQLite.
+	DB_OPERATION_TIMEOUT time.Duration `mapstructure:"DB_OPERATION_TIMEOUT"`
+	// MONGO_URI is a full connection string; when empty it is built from the other MONGO_* settings.
+	MONGO_URI      string `mapstructure:"MONGO_URI" secret:"true"`
 	MONGO_USER     string `mapstructure:"MONGO_USER"`
-	MONGO_PASSWORD string `mapstructure:"MONGO_PASSWORD"`
+	MONGO_PASSWORD string `mapstructure:"MONGO_PASSWORD" secret:"true"`
 	MONGO_HOST     string `mapstructure:"MONGO_HOST"`
 	MONGO_PORT     string `mapstructure:"MONGO_PORT"`
 	MONGO_DATABASE string `mapstructure:"MONGO_DATABASE"`
-	HTTP_PORT      string `mapstructure:"HTTP_PORT"`
+	// MONGO_AUTH_SOURCE is the database holding MONGO_USER and defaults to its name, so that
+	// URIs built by older releases keep working.
+	MONGO_AUTH_SOURCE string `mapstructure:"MONGO_AUTH_SOURCE"`
+	MONGO_TLS         bool   `mapstructure:"MONGO_TLS"`
+	// MONGO_TLS_CA_FILE is a PEM bundle trusted in addition to the system roots.
+	MONGO_TLS_CA_FILE              string        `mapstructure:"MONGO_TLS_CA_FILE"`
+	MONGO_CONNECT_TIMEOUT          time.Duration `mapstructure:"MONGO_CONNECT_TIMEOUT"`
+	MONGO_SERVER_SELECTION_TIMEOUT time.Duration `mapstructure:"MONGO_SERVER_SELECTION_TIMEOUT"`
+	MONGO_MAX_POOL_SIZE            uint64        `mapstructure:"MONGO_MAX_POOL_SIZE"`
+	MONGO_MIN_POOL_SIZE            uint64        `mapstructure:"MONGO_MIN_POOL_SIZE"`
+	// MONGO_STARTUP_TIMEOUT is how long startup keeps retrying to reach MongoDB.
+	MONGO_STARTUP_TIMEOUT time.Duration `mapstructure:"MONGO_STARTUP_TIMEOUT"`
+	// MONGO_READ_PREFERENCE is primary (default), primaryPreferred, secondary, secondaryPreferred or nearest.
+	MONGO_READ_PREFERENCE string `mapstructure:"MONGO_READ_PREFERENCE"`
+	// MONGO_WRITE_CONCERN is majority (default) or the number of members that must acknowledge a write.
+	MONGO_WRITE_CONCERN        string        `mapstructure:"MONGO_WRITE_CONCERN"`
+	POSTGRES_USER              string        `mapstructure:"POSTGRES_USER"`
+	POSTGRES_PASSWORD          string        `mapstructure:"POSTGRES_PASSWORD" secret:"true"`
+	POSTGRES_HOST              string        `mapstructure:"POSTGRES_HOST"`
+	POSTGRES_PORT              string        `mapstructure:"POSTGRES_PORT"`
+	POSTGRES_DATABASE          string        `mapstructure:"POSTGRES_DATABASE"`
+	POSTGRES_SSLMODE           string        `mapstructure:"POSTGRES_SSLMODE"`
+	POSTGRES_MAX_OPEN_CONNS    int           `mapstructure:"POSTGRES_MAX_OPEN_CONNS"`
+	POSTGRES_MAX_IDLE_CONNS    int           `mapstructure:"POSTGRES_MAX_IDLE_CONNS"`
+	POSTGRES_CONN_MAX_LIFETIME time.Duration `mapstructure:"POSTGRES_CONN_MAX_LIFETIME"`
+	SQLITE_PATH                string        `mapstructure:"SQLITE_PATH"`
+	// BACKUP_TOKEN enables GET /backup with DB_DRIVER=sqlite for requests presenting it as a
+	// bearer token. The endpoint is off while it is empty (default).
+	BACKUP_TOKEN string `mapstructure:"BACKUP_TOKEN" secret:"true"`
+	// ADMIN_TOKEN enables the /webhooks admin routes for requests presenting it as a bearer
+	// token. The routes are off while it is empty (default).
+	ADMIN_TOKEN string `mapstructure:"ADMIN_TOKEN" secret:"true"`
+	HTTP_PORT   string `mapstructure:"HTTP_PORT"`
+	// HTTP_TLS_CERT_FILE and HTTP_TLS_KEY_FILE make the API serve HTTPS when both are set.
+	HTTP_TLS_CERT_FILE string        `mapstructure:"HTTP_TLS_CERT_FILE"`
+	HTTP_TLS_KEY_FILE  string        `mapstructure:"HTTP_TLS_KEY_FILE"`
+	HTTP_READ_TIMEOUT  time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
+	HTTP_WRITE_TIMEOUT time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	HTTP_IDLE_TIMEOUT  time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`
+	// SHUTDOWN_TIMEOUT bounds how long in-flight requests and background workers may take to drain.
+	SHUTDOWN_TIMEOUT time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	// SHUTDOWN_DELAY is how long /readyz fails before the API stops accepting connections,
+	// giving load balancers time to take the instance out of rotation.
+	SHUTDOWN_DELAY time.Duration `mapstructure:"SHUTDOWN_DELAY"`
+	// EVENT_PUBLISHER selects where outbox events are delivered: bus (default), webhook or nats.
+	EVENT_PUBLISHER           string        `mapstructure:"EVENT_PUBLISHER"`
+	EVENT_WEBHOOK_URL         string        `mapstructure:"EVENT_WEBHOOK_URL"`
+	NATS_URL                  string        `mapstructure:"NATS_URL"`
+	NATS_SUBJECT_PREFIX       string        `mapstructure:"NATS_SUBJECT_PREFIX"`
+	OUTBOX_RELAY_INTERVAL     time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	WEBHOOK_DELIVERY_INTERVAL time.Duration `mapstructure:"WEBHOOK_DELIVERY_INTERVAL"`
+	// LEGACY_ROUTES_ENABLED keeps serving the unversioned routes as deprecated aliases of /v1
+	// until LEGACY_ROUTES_SUNSET, a date such as 2027-04-30 announced in the Sunset header.
+	LEGACY_ROUTES_ENABLED bool   `mapstructure:"LEGACY_ROUTES_ENABLED"`
+	LEGACY_ROUTES_SUNSET  string `mapstructure:"LEGACY_ROUTES_SUNSET"`
+	// SWAGGER_ENABLED serves the Swagger UI of each API version on /docs/<version>.
+	SWAGGER_ENABLED bool `mapstructure:"SWAGGER_ENABLED"`
+	// OTEL_TRACES_EXPORTER selects where spans are sent: none (default), otlp or stdout.
+	OTEL_TRACES_EXPORTER string `mapstructure:"OTEL_TRACES_EXPORTER"`
+	OTEL_SERVICE_NAME    string `mapstructure:"OTEL_SERVICE_NAME"`
+	// LOG_LEVEL is debug, info (default), warn or error.
+	LOG_LEVEL string `mapstructure:"LOG_LEVEL"`
 }
 
-func GetConfig() *Configs {
-	return &Configs{
-		MONGO_USER:     os.Getenv("MONGO_USER"),
-		MONGO_PASSWORD: os.Getenv("MONGO_PASSWORD"),
-		MONGO_HOST:     os.Getenv("MONGO_HOST"),
-		MONGO_PORT:     os.Getenv("MONGO_PORT"),
-		MONGO_DATABASE: os.Getenv("MONGO_DATABASE"),
-		HTTP_PORT:      os.Getenv("HTTP_PORT"),
+func defaults() Configs {
+	return Configs{
+		DB_DRIVER:                      "mongo",
+		DB_OPERATION_TIMEOUT:           5 * time.Second,
+		MONGO_PORT:                     "27017",
+		MONGO_CONNECT_TIMEOUT:          10 * time.Second,
+		MONGO_SERVER_SELECTION_TIMEOUT: 10 * time.Second,
+		MONGO_MAX_POOL_SIZE:            100,
+		MONGO_STARTUP_TIMEOUT:          time.Minute,
+		MONGO_READ_PREFERENCE:          "primary",
+		MONGO_WRITE_CONCERN:            "majority",
+		POSTGRES_PORT:                  "5432",
+		POSTGRES_SSLMODE:               "disable",
+		POSTGRES_MAX_OPEN_CONNS:        25,
+		POSTGRES_MAX_IDLE_CONNS:        5,
+		POSTGRES_CONN_MAX_LIFETIME:     30 * time.Minute,
+		SQLITE_PATH:                    "skinaapis.db",
+		HTTP_PORT:                      "9090",
+		HTTP_READ_TIMEOUT:              15 * time.Second,
+		HTTP_WRITE_TIMEOUT:             30 * time.Second,
+		HTTP_IDLE_TIMEOUT:              60 * time.Second,
+		SHUTDOWN_TIMEOUT:               30 * time.Second,
+		SHUTDOWN_DELAY:                 5 * time.Second,
+		EVENT_PUBLISHER:                "bus",
+		NATS_URL:                       "nats://localhost:4222",
+		NATS_SUBJECT_PREFIX:            "skinaapis.events",
+		OUTBOX_RELAY_INTERVAL:          time.Second,
+		WEBHOOK_DELIVERY_INTERVAL:      time.Second,
+		LEGACY_ROUTES_ENABLED:          true,
+		LEGACY_ROUTES_SUNSET:           "2027-04-30",
+		SWAGGER_ENABLED:                true,
+		OTEL_TRACES_EXPORTER:           "none",
+		OTEL_SERVICE_NAME:              "skinaapis",
+		LOG_LEVEL:                      "info",
 	}
 }
@@ -0,0 +1,108 @@
+package configs
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// flagKeys are the settings that can also be given on the command line, by flag name.
+var flagKeys = map[string]struct {
+	key   string
+	usage string
+}{
+	"repo":      {"DB_DRIVER", "storage backend: mongo, postgres, sqlite or memory (overrides DB_DRIVER)"},
+	"http-port": {"HTTP_PORT", "port the API listens on (overrides HTTP_PORT)"},
+	"log-level": {"LOG_LEVEL", "debug, info, warn or error (overrides LOG_LEVEL)"},
+}
+
+// Load registers the configuration flags on fs, parses args and returns the validated
+// configuration. Settings are merged from lowest to highest precedence:
+//
+//  1. the defaults
+//  2. the YAML, TOML or JSON file given with -config or CONFIG_FILE
+//  3. environment variables named after the settings, e.g. HTTP_PORT
+//  4. the flags, e.g. -http-port
+//
+// A secret can instead be read from the file named by <SETTING>_FILE, e.g. MONGO_PASSWORD_FILE.
+func Load(fs *flag.FlagSet, args []string) (*Configs, error) {
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML, TOML or JSON configuration file (defaults to CONFIG_FILE)")
+	flagValues := map[string]*string{}
+	for name, f := range flagKeys {
+		flagValues[name] = fs.String(name, "", f.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	for key, value := range settings(defaults()) {
+		v.SetDefault(key, value)
+	}
+
+	if *configFile != "" {
+		v.SetConfigFile(*configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("reading config file %s: %w", *configFile, err)
+		}
+	}
+
+	v.AutomaticEnv()
+
+	if err := readSecretFiles(v); err != nil {
+		return nil, err
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for name, f := range flagKeys {
+		if set[name] {
+			v.Set(f.key, *flagValues[name])
+		}
+	}
+
+	var config Configs
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("decoding configuration: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// readSecretFiles sets every setting whose <SETTING>_FILE variable names a file to the
+// contents of that file, without the trailing newline.
+func readSecretFiles(v *viper.Viper) error {
+	for key := range settings(defaults()) {
+		path := os.Getenv(key + "_FILE")
+		if path == "" {
+			continue
+		}
+		if os.Getenv(key) != "" {
+			return fmt.Errorf("set either %s or %s_FILE, not both", key, key)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s_FILE: %w", key, err)
+		}
+		v.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
+
+// settings returns the fields of c by setting name.
+func settings(c Configs) map[string]any {
+	values := map[string]any{}
+	rv := reflect.ValueOf(c)
+	for i := 0; i < rv.NumField(); i++ {
+		values[rv.Type().Field(i).Tag.Get("mapstructure")] = rv.Field(i).Interface()
+	}
+	return values
+}
@@ -0,0 +1,50 @@
+package configs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+const masked = "*****"
+
+// Print writes the configuration as YAML, one setting per line in declaration order.
+// Secrets are masked and passwords embedded in URLs are redacted, so the output is safe
+// to log or paste into a ticket.
+func (c *Configs) Print(w io.Writer) error {
+	rv := reflect.ValueOf(*c)
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+
+		var value any = rv.Field(i).Interface()
+		switch v := value.(type) {
+		case time.Duration:
+			value = v.String()
+		case string:
+			value = redactURL(v)
+			if field.Tag.Get("secret") == "true" && v != "" {
+				value = masked
+			}
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", field.Tag.Get("mapstructure"), encoded); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	return u.Redacted()
+}
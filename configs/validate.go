@@ -0,0 +1,102 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate reports every invalid setting at once, one per line.
+func (c *Configs) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value))
+	}
+	positive := func(key string, d time.Duration) {
+		check(d > 0, "%s must be a positive duration such as 30s, got %s", key, d)
+	}
+	port := func(key, value string) {
+		n, err := strconv.Atoi(value)
+		check(err == nil && n > 0 && n < 65536, "%s must be a port number between 1 and 65535, got %q", key, value)
+	}
+
+	oneOf("DB_DRIVER", c.DB_DRIVER, "mongo", "postgres", "sqlite", "memory")
+	positive("DB_OPERATION_TIMEOUT", c.DB_OPERATION_TIMEOUT)
+	switch c.DB_DRIVER {
+	case "mongo":
+		if c.MONGO_URI != "" {
+			u, err := url.Parse(c.MONGO_URI)
+			check(err == nil && (u.Scheme == "mongodb" || u.Scheme == "mongodb+srv"), "MONGO_URI must be a mongodb:// or mongodb+srv:// URI")
+			check(c.MONGO_DATABASE != "" || (err == nil && strings.Trim(u.Path, "/") != ""), "MONGO_DATABASE is required when MONGO_URI does not name a database")
+		} else {
+			check(c.MONGO_HOST != "", "MONGO_HOST is required when MONGO_URI is not set")
+			check(c.MONGO_DATABASE != "", "MONGO_DATABASE is required when MONGO_URI is not set")
+			check((c.MONGO_USER == "") == (c.MONGO_PASSWORD == ""), "MONGO_USER and MONGO_PASSWORD must be set together")
+			port("MONGO_PORT", c.MONGO_PORT)
+		}
+		positive("MONGO_CONNECT_TIMEOUT", c.MONGO_CONNECT_TIMEOUT)
+		positive("MONGO_SERVER_SELECTION_TIMEOUT", c.MONGO_SERVER_SELECTION_TIMEOUT)
+		check(c.MONGO_MAX_POOL_SIZE == 0 || c.MONGO_MIN_POOL_SIZE <= c.MONGO_MAX_POOL_SIZE,
+			"MONGO_MIN_POOL_SIZE (%d) must not exceed MONGO_MAX_POOL_SIZE (%d)", c.MONGO_MIN_POOL_SIZE, c.MONGO_MAX_POOL_SIZE)
+		check(c.MONGO_TLS_CA_FILE == "" || c.MONGO_TLS, "MONGO_TLS_CA_FILE requires MONGO_TLS=true")
+		positive("MONGO_STARTUP_TIMEOUT", c.MONGO_STARTUP_TIMEOUT)
+		oneOf("MONGO_READ_PREFERENCE", c.MONGO_READ_PREFERENCE, "primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest")
+		if c.MONGO_WRITE_CONCERN != "majority" {
+			n, err := strconv.Atoi(c.MONGO_WRITE_CONCERN)
+			check(err == nil && n >= 1, "MONGO_WRITE_CONCERN must be majority or a number of members of at least 1, got %q", c.MONGO_WRITE_CONCERN)
+		}
+	case "postgres":
+		check(c.POSTGRES_HOST != "", "POSTGRES_HOST is required when DB_DRIVER is postgres")
+		check(c.POSTGRES_DATABASE != "", "POSTGRES_DATABASE is required when DB_DRIVER is postgres")
+		check(c.POSTGRES_USER != "", "POSTGRES_USER is required when DB_DRIVER is postgres")
+		port("POSTGRES_PORT", c.POSTGRES_PORT)
+		oneOf("POSTGRES_SSLMODE", c.POSTGRES_SSLMODE, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+		check(c.POSTGRES_MAX_OPEN_CONNS > 0, "POSTGRES_MAX_OPEN_CONNS must be greater than zero, got %d", c.POSTGRES_MAX_OPEN_CONNS)
+		check(c.POSTGRES_MAX_IDLE_CONNS >= 0 && c.POSTGRES_MAX_IDLE_CONNS <= c.POSTGRES_MAX_OPEN_CONNS,
+			"POSTGRES_MAX_IDLE_CONNS must be between 0 and POSTGRES_MAX_OPEN_CONNS (%d), got %d", c.POSTGRES_MAX_OPEN_CONNS, c.POSTGRES_MAX_IDLE_CONNS)
+	case "sqlite":
+		check(c.SQLITE_PATH != "", "SQLITE_PATH is required when DB_DRIVER is sqlite")
+	}
+	check(c.BACKUP_TOKEN == "" || c.DB_DRIVER == "sqlite", "BACKUP_TOKEN requires DB_DRIVER=sqlite")
+	check(c.BACKUP_TOKEN == "" || len(c.BACKUP_TOKEN) >= 32, "BACKUP_TOKEN must be at least 32 characters long")
+	check(c.ADMIN_TOKEN == "" || len(c.ADMIN_TOKEN) >= 32, "ADMIN_TOKEN must be at least 32 characters long")
+
+	port("HTTP_PORT", c.HTTP_PORT)
+	check((c.HTTP_TLS_CERT_FILE == "") == (c.HTTP_TLS_KEY_FILE == ""), "HTTP_TLS_CERT_FILE and HTTP_TLS_KEY_FILE must be set together")
+	positive("HTTP_READ_TIMEOUT", c.HTTP_READ_TIMEOUT)
+	positive("HTTP_WRITE_TIMEOUT", c.HTTP_WRITE_TIMEOUT)
+	positive("HTTP_IDLE_TIMEOUT", c.HTTP_IDLE_TIMEOUT)
+	positive("SHUTDOWN_TIMEOUT", c.SHUTDOWN_TIMEOUT)
+	check(c.SHUTDOWN_DELAY >= 0, "SHUTDOWN_DELAY must not be negative, got %s", c.SHUTDOWN_DELAY)
+
+	if c.LEGACY_ROUTES_ENABLED {
+		_, err := time.Parse(time.DateOnly, c.LEGACY_ROUTES_SUNSET)
+		check(err == nil, "LEGACY_ROUTES_SUNSET must be a date such as 2027-04-30, got %q", c.LEGACY_ROUTES_SUNSET)
+	}
+
+	oneOf("EVENT_PUBLISHER", c.EVENT_PUBLISHER, "bus", "webhook", "nats")
+	check(c.EVENT_PUBLISHER != "webhook" || c.EVENT_WEBHOOK_URL != "", "EVENT_WEBHOOK_URL is required when EVENT_PUBLISHER is webhook")
+	positive("OUTBOX_RELAY_INTERVAL", c.OUTBOX_RELAY_INTERVAL)
+	positive("WEBHOOK_DELIVERY_INTERVAL", c.WEBHOOK_DELIVERY_INTERVAL)
+
+	oneOf("OTEL_TRACES_EXPORTER", c.OTEL_TRACES_EXPORTER, "none", "otlp", "stdout")
+	oneOf("LOG_LEVEL", strings.ToLower(c.LOG_LEVEL), "debug", "info", "warn", "error")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
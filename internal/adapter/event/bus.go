@@ -0,0 +1,46 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// Handler reacts to an event delivered by the Bus.
+type Handler func(ctx context.Context, event domain.Event) error
+
+// Bus is an in-process port.EventPublisher that hands every event to the handlers
+// subscribed to its type. Handlers subscribed to "*" receive every event.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish runs the handlers synchronously and fails if any of them fails, so the
+// outbox relay retries the event; handlers must therefore tolerate duplicates.
+func (b *Bus) Publish(ctx context.Context, event domain.Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.handlers[event.Type]...), b.handlers["*"]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
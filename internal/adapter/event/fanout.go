@@ -0,0 +1,87 @@
+package event
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+// fanoutMaxPending bounds how many partly delivered events a Fanout remembers.
+const fanoutMaxPending = 10000
+
+// Fanout publishes every event to all of its publishers. When some of them fail, the
+// retried event only goes to those, so the others, e.g. the metrics, do not count it
+// twice. What was delivered is remembered in memory, so after a restart the pending
+// events are published to all publishers again, which the at least once contract allows.
+// The outbox relay retries without end, so while a publisher stays down the oldest
+// events are forgotten beyond fanoutMaxPending and, likewise, go to every publisher.
+type Fanout struct {
+	publishers []port.EventPublisher
+	maxPending int
+	mu         sync.Mutex
+	// delivered holds, for the events that failed on some publisher, which publishers
+	// already took them; pending orders them from the oldest failure.
+	delivered map[uuid.UUID][]bool
+	pending   *list.List
+	elements  map[uuid.UUID]*list.Element
+}
+
+func NewFanout(publishers ...port.EventPublisher) *Fanout {
+	return &Fanout{
+		publishers: publishers,
+		maxPending: fanoutMaxPending,
+		delivered:  make(map[uuid.UUID][]bool),
+		pending:    list.New(),
+		elements:   make(map[uuid.UUID]*list.Element),
+	}
+}
+
+func (f *Fanout) Publish(ctx context.Context, event domain.Event) error {
+	f.mu.Lock()
+	delivered, ok := f.delivered[event.ID]
+	f.mu.Unlock()
+	if !ok {
+		delivered = make([]bool, len(f.publishers))
+	}
+
+	var errs []error
+	for i, publisher := range f.publishers {
+		if delivered[i] {
+			continue
+		}
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delivered[i] = true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(errs) == 0 {
+		f.forget(event.ID)
+		return nil
+	}
+	if _, ok := f.elements[event.ID]; !ok {
+		for f.pending.Len() >= f.maxPending {
+			f.forget(f.pending.Front().Value.(uuid.UUID))
+		}
+		f.elements[event.ID] = f.pending.PushBack(event.ID)
+	}
+	f.delivered[event.ID] = delivered
+	return errors.Join(errs...)
+}
+
+// forget drops what f remembers of the event with the given ID. f.mu must be held.
+func (f *Fanout) forget(id uuid.UUID) {
+	if element, ok := f.elements[id]; ok {
+		f.pending.Remove(element)
+		delete(f.elements, id)
+	}
+	delete(f.delivered, id)
+}
@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// countingPublisher counts the events it gets and fails the first failures of them.
+type countingPublisher struct {
+	failures  int
+	published int
+}
+
+func (p *countingPublisher) Publish(ctx context.Context, event domain.Event) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	p.published++
+	return nil
+}
+
+func TestFanoutRetriesOnlyFailedPublishers(t *testing.T) {
+	ctx := context.Background()
+	metrics, broker := &countingPublisher{}, &countingPublisher{failures: 2}
+	fanout := NewFanout(metrics, broker)
+	event := domain.Event{ID: uuid.New(), Type: domain.EventOrderCreated}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if err := fanout.Publish(ctx, event); err == nil {
+			t.Fatalf("attempt %d: Publish succeeded while the broker failed", attempt)
+		}
+	}
+	if err := fanout.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if metrics.published != 1 || broker.published != 1 {
+		t.Fatalf("published %d times to metrics and %d to the broker, want once each", metrics.published, broker.published)
+	}
+
+	// Replayed once delivered, e.g. after its lease expired, the event goes to every publisher again.
+	if err := fanout.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if metrics.published != 2 || broker.published != 2 {
+		t.Fatalf("published %d times to metrics and %d to the broker, want twice each", metrics.published, broker.published)
+	}
+	if len(fanout.delivered) != 0 {
+		t.Errorf("Fanout still tracks %d delivered events", len(fanout.delivered))
+	}
+}
+
+func TestFanoutForgetsTheOldestEventsBeyondItsLimit(t *testing.T) {
+	ctx := context.Background()
+	metrics, broker := &countingPublisher{}, &countingPublisher{failures: 4}
+	fanout := NewFanout(metrics, broker)
+	fanout.maxPending = 2
+	events := []domain.Event{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+
+	for _, event := range events {
+		if err := fanout.Publish(ctx, event); err == nil {
+			t.Fatalf("Publish succeeded while the broker failed")
+		}
+	}
+	if len(fanout.delivered) != 2 {
+		t.Fatalf("Fanout tracks %d events, want its limit of 2", len(fanout.delivered))
+	}
+	if _, ok := fanout.delivered[events[0].ID]; ok {
+		t.Fatal("Fanout kept the oldest event beyond its limit")
+	}
+
+	// The forgotten event goes to every publisher again, the remembered ones only to the broker.
+	if err := fanout.Publish(ctx, events[1]); err == nil {
+		t.Fatal("Publish succeeded while the broker failed")
+	}
+	if err := fanout.Publish(ctx, events[0]); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if metrics.published != 4 {
+		t.Errorf("published %d times to metrics, want 4", metrics.published)
+	}
+	if len(fanout.delivered) != 2 || fanout.pending.Len() != 2 || len(fanout.elements) != 2 {
+		t.Errorf("Fanout tracks %d events, want the 2 that still failed", len(fanout.delivered))
+	}
+}
@@ -0,0 +1,46 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes every event to the subject <prefix>.<event type> on a NATS
+// compatible server. The event ID is sent as the Nats-Msg-Id header, which JetStream
+// uses to discard duplicates.
+type NATSPublisher struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+func NewNATSPublisher(url string, prefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("skinaapis"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSPublisher{conn: conn, prefix: prefix}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event domain.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(p.prefix + "." + event.Type)
+	msg.Data = data
+	msg.Header.Set(nats.MsgIdHdr, event.ID.String())
+
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) Close() {
+	p.conn.Close()
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "github.com/mfritschdotgo/techchallenge/internal/core/domain"
+
+// List is the envelope of every paginated list response.
+type List struct {
+	Items    any   `json:"items" swaggertype:"array,object"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"pageSize"`
+	Total    int64 `json:"total"`
+	HasNext  bool  `json:"hasNext"`
+	// Next is set by lists that can go on by cursor, see CursorList, to the token of the
+	// last item when another page follows.
+	Next string `json:"next,omitempty"`
+}
+
+// NewList wraps one page of items, encoding an empty page as [] rather than null.
+func NewList[T any](items []T, page, pageSize int, total int64) List {
+	if items == nil {
+		items = []T{}
+	}
+	return List{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasNext:  int64(page*pageSize) < total,
+	}
+}
+
+// CursorList is the envelope of cursor-paginated list responses. Next and Prev are the
+// tokens to pass as after and before to read the following and preceding pages.
+type CursorList struct {
+	Items    any    `json:"items" swaggertype:"array,object"`
+	PageSize int    `json:"pageSize"`
+	Next     string `json:"next,omitempty"`
+	Prev     string `json:"prev,omitempty"`
+}
+
+// NewOrderCursorList wraps a page of orders with their CPF masked, like the order list.
+func NewOrderCursorList(page *domain.OrderCursorPage, pageSize int) CursorList {
+	list := CursorList{Items: NewOrderSummaries(page.Orders), PageSize: pageSize}
+	if page.Next != nil {
+		list.Next = page.Next.String()
+	}
+	if page.Prev != nil {
+		list.Prev = page.Prev.String()
+	}
+	return list
+}
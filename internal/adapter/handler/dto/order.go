@@ -1,12 +1,37 @@
 package dto
 
-type ProductItem struct {
-	ID          string `json:"id"`
-	Quantity    int64  `json:"quantity"`
-	ProductName string `json:"product_name" swaggerignore:"true"`
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// OrderSummary is the representation of an order in list responses, with the client CPF masked.
+type OrderSummary struct {
+	ID                uuid.UUID          `json:"id"`
+	Client            string             `json:"client"`
+	Items             []domain.OrderItem `json:"items"`
+	Total             float64            `json:"total"`
+	Status            int                `json:"status"`
+	StatusDescription string             `json:"status_description"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
 }
 
-type CreateOrderRequest struct {
-	Client   string        `json:"client"`
-	Products []ProductItem `json:"products"`
+func NewOrderSummaries(orders []domain.Order) []OrderSummary {
+	summaries := make([]OrderSummary, 0, len(orders))
+	for _, order := range orders {
+		summaries = append(summaries, OrderSummary{
+			ID:                order.ID,
+			Client:            order.Client.Masked(),
+			Items:             order.Items,
+			Total:             order.Total,
+			Status:            order.Status,
+			StatusDescription: order.StatusDescription,
+			CreatedAt:         order.CreatedAt,
+			UpdatedAt:         order.UpdatedAt,
+		})
+	}
+	return summaries
 }
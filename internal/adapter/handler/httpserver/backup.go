@@ -0,0 +1,42 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+type BackupHandler struct {
+	backup port.DatabaseBackup
+}
+
+func NewBackupHandler(b port.DatabaseBackup) *BackupHandler {
+	return &BackupHandler{
+		backup: b,
+	}
+}
+
+// Backup downloads a snapshot of the database
+// @Summary Download a database backup
+// @Description Takes an online snapshot of the SQLite database and returns it as a file. Only available when the API runs with DB_DRIVER=sqlite and BACKUP_TOKEN set, to callers sending it as a bearer token.
+// @Tags backup
+// @Produce octet-stream
+// @Param Authorization header string true "Bearer followed by BACKUP_TOKEN"
+// @Success 200 {file} file "SQLite database file"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 500 "Internal server error if the snapshot could not be taken"
+// @Router /backup [get]
+func (h *BackupHandler) Backup(w http.ResponseWriter, r *http.Request) {
+	filename := fmt.Sprintf("skinaapis-%s.db", time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if err := h.backup.Backup(r.Context(), w); err != nil {
+		// Once the snapshot is being written the status can no longer change,
+		// so the client only notices the truncated download.
+		w.Header().Del("Content-Disposition")
+		internalError(w, r, err, "Failed to back up the database")
+	}
+}
@@ -2,22 +2,22 @@ package httpserver
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi"
 
 	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
-	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
 )
 
 type CategoryHandler struct {
-	service *service.CategoryService
+	service port.CategoryService
 }
 
-func NewCategoryHandler(s *service.CategoryService) *CategoryHandler {
+func NewCategoryHandler(s port.CategoryService) *CategoryHandler {
 	return &CategoryHandler{
 		service: s,
 	}
@@ -29,14 +29,15 @@ func NewCategoryHandler(s *service.CategoryService) *CategoryHandler {
 // @Tags categories
 // @Accept json
 // @Produce json
-// @Param		request	body		dto.CreateCategoryRequest	true	"Category creation details"
+// @Param		request	body		port.CreateCategoryRequest	true	"Category creation details"
 // @Success 201 {object} domain.Category "Successfully created Category"
 // @Failure 400 "Bad request if the Category data is invalid"
+// @Failure 409 "Conflict if a category with the same name already exists"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /categories [post]
+// @Router /v1/categories [post]
 func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	var categoryDto dto.CreateCategoryRequest
+	var categoryDto port.CreateCategoryRequest
 	if err := json.NewDecoder(r.Body).Decode(&categoryDto); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -44,7 +45,13 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 
 	category, err := h.service.CreateCategory(ctx, categoryDto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, domain.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else {
+			internalError(w, r, err, err.Error())
+		}
 		return
 	}
 
@@ -60,12 +67,13 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 // @Accept json
 // @Produce json
 // @Param id path string true "Category ID"
-// @Param		request	body		dto.CreateCategoryRequest	true	"Category object that needs to be updated"
+// @Param		request	body		port.CreateCategoryRequest	true	"Category object that needs to be updated"
 // @Success 200 {object} domain.Category "Category successfully updated"
 // @Failure 400 {string} string "Invalid input, Object is invalid"
 // @Failure 404 {string} string "Category not found"
+// @Failure 409 {string} string "A category with the same name already exists"
 // @Failure 500 {string} string "Internal server error"
-// @Router /categories/{id} [put]
+// @Router /v1/categories/{id} [put]
 func (h *CategoryHandler) ReplaceCategory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -84,10 +92,14 @@ func (h *CategoryHandler) ReplaceCategory(w http.ResponseWriter, r *http.Request
 	response, err := h.service.ReplaceCategory(ctx, id, &category)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, domain.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Category not found", http.StatusNotFound)
 		} else {
-			http.Error(w, "Error replacing category", http.StatusInternalServerError)
+			internalError(w, r, err, "Error replacing category")
 		}
 		return
 	}
@@ -104,12 +116,13 @@ func (h *CategoryHandler) ReplaceCategory(w http.ResponseWriter, r *http.Request
 // @Accept json
 // @Produce json
 // @Param id path string true "Category ID"
-// @Param		request	body		dto.CreateCategoryRequest	true	"Category object that needs to be updated"
+// @Param		request	body		port.CreateCategoryRequest	true	"Category object that needs to be updated"
 // @Success 200 {object} domain.Category "Category successfully updated"
 // @Failure 400 {string} string "Invalid input, Object is invalid"
 // @Failure 404 {string} string "Category not found"
+// @Failure 409 {string} string "A category with the same name already exists"
 // @Failure 500 {string} string "Internal server error"
-// @Router /categories/{id} [patch]
+// @Router /v1/categories/{id} [patch]
 func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -127,10 +140,12 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 	response, err := h.service.UpdateCategory(ctx, id, &category)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, domain.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Category not found", http.StatusNotFound)
 		} else {
-			http.Error(w, "Error updating category", http.StatusInternalServerError)
+			internalError(w, r, err, "Error updating category")
 		}
 		return
 	}
@@ -150,7 +165,7 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} domain.Category "Successfully retrieved the category details"
 // @Failure 400 "Bad request if the ID is not provided or invalid"
 // @Failure 404 "Product not found if the ID does not match any category"
-// @Router /categories/{id} [get]
+// @Router /v1/categories/{id} [get]
 func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -177,31 +192,21 @@ func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number for pagination" default(1)
-// @Param pageSize query int false "Number of categories per page" default(10)
-// @Success 200 {array} domain.Category "Successfully retrieved list of categories"
+// @Param pageSize query int false "Number of categories per page" default(10) maximum(100)
+// @Success 200 {object} dto.List{items=[]domain.Category} "Successfully retrieved list of categories"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /categories [get]
+// @Router /v1/categories [get]
 func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	page, err := strconv.Atoi(r.URL.Query().Get("page"))
-	if err != nil || page < 1 {
-		page = 1 // Default to page 1 if non-positive or non-numeric
-	}
-
-	size, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
-	if err != nil || size <= 0 {
-		size = 10 // Default size if non-positive or non-numeric
-	}
+	page, size := pageParams(r)
 
-	categories, err := h.service.GetCategories(ctx, page, size)
+	categories, total, err := h.service.GetCategories(ctx, page, size)
 	if err != nil {
-		http.Error(w, "Failed to retrieve categories", http.StatusInternalServerError)
+		internalError(w, r, err, "Failed to retrieve categories")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(categories)
+	writeList(w, r, dto.NewList(categories, page, size, total))
 }
 
 // DeleteCategory deletes a category by its ID
@@ -214,8 +219,9 @@ func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} map[string]string "Message indicating successful deletion"
 // @Failure 400 "Bad request if the ID is not provided or is invalid"
 // @Failure 404 "category not found if the ID does not match any category"
+// @Failure 409 "Conflict if products still belong to the category (PostgreSQL storage only)"
 // @Failure 500 "Internal server error if there is a problem deleting the category"
-// @Router /categories/{id} [delete]
+// @Router /v1/categories/{id} [delete]
 func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -225,7 +231,11 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := h.service.DeleteCategory(ctx, id); err != nil {
-		http.Error(w, "Category not found or error deleting category", http.StatusNotFound)
+		if errors.Is(err, domain.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else {
+			http.Error(w, "Category not found or error deleting category", http.StatusNotFound)
+		}
 		return
 	}
 
@@ -233,6 +243,6 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 	response := map[string]string{"message": "Category with ID " + id + " deleted successfully."}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		internalError(w, r, err, "Failed to encode response")
 	}
 }
@@ -0,0 +1,77 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+// fakeCategoryService answers CreateCategory with category and err and records the
+// request it got. The other methods are not used by the tests.
+type fakeCategoryService struct {
+	port.CategoryService
+	category *domain.Category
+	err      error
+	got      *port.CreateCategoryRequest
+}
+
+func (f *fakeCategoryService) CreateCategory(ctx context.Context, request port.CreateCategoryRequest) (*domain.Category, error) {
+	f.got = &request
+	return f.category, f.err
+}
+
+func TestCreateCategory(t *testing.T) {
+	category, err := domain.NewCategory("Lanche", "Hamburgers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lanche := &port.CreateCategoryRequest{Name: "Lanche", Description: "Hamburgers"}
+	for _, tc := range []struct {
+		name    string
+		body    string
+		service *fakeCategoryService
+		want    int
+		// wantRequest is what the service must get, nil when it must not be called.
+		wantRequest *port.CreateCategoryRequest
+	}{
+		{"Created", `{"name":"Lanche","description":"Hamburgers"}`, &fakeCategoryService{category: category}, http.StatusCreated, lanche},
+		{"Invalid", `{"description":"Hamburgers"}`, &fakeCategoryService{err: fmt.Errorf("%w: name is required", domain.ErrValidation)}, http.StatusBadRequest, &port.CreateCategoryRequest{Description: "Hamburgers"}},
+		{"Duplicate", `{"name":"Lanche","description":"Hamburgers"}`, &fakeCategoryService{err: fmt.Errorf("%w: category name already exists", domain.ErrConflict)}, http.StatusConflict, lanche},
+		{"Failure", `{"name":"Lanche","description":"Hamburgers"}`, &fakeCategoryService{err: errors.New("connection refused")}, http.StatusInternalServerError, lanche},
+		{"MalformedBody", `{"name":`, &fakeCategoryService{}, http.StatusBadRequest, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/categories", strings.NewReader(tc.body))
+			NewCategoryHandler(tc.service).CreateCategory(rec, r)
+
+			if rec.Code != tc.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.want, rec.Body)
+			}
+			if !reflect.DeepEqual(tc.service.got, tc.wantRequest) {
+				t.Fatalf("the service got %+v, want %+v", tc.service.got, tc.wantRequest)
+			}
+			if tc.want != http.StatusCreated {
+				return
+			}
+
+			var body domain.Category
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.ID != category.ID || body.Name != category.Name {
+				t.Errorf("body = %+v, want %+v", body, category)
+			}
+		})
+	}
+}
@@ -2,19 +2,20 @@ package httpserver
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi"
 
-	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
-	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
 )
 
 type ClientHandler struct {
-	service *service.ClientService
+	service port.ClientService
 }
 
-func NewClientHandler(s *service.ClientService) *ClientHandler {
+func NewClientHandler(s port.ClientService) *ClientHandler {
 	return &ClientHandler{
 		service: s,
 	}
@@ -26,15 +27,16 @@ func NewClientHandler(s *service.ClientService) *ClientHandler {
 // @Tags clients
 // @Accept json
 // @Produce json
-// @Param		request	body		dto.CreateClientRequest	true	"Client creation details"
+// @Param		request	body		port.CreateClientRequest	true	"Client creation details"
 // @Success 201 {object} domain.Client "Client successfully created"
 // @Failure 400 "Bad request if the Client data is invalid"
+// @Failure 409 "Conflict if a client with the same CPF already exists"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /clients [post]
+// @Router /v1/clients [post]
 func (h *ClientHandler) CreateClient(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context() // Get the request context
 
-	var clientDto dto.CreateClientRequest
+	var clientDto port.CreateClientRequest
 	if err := json.NewDecoder(r.Body).Decode(&clientDto); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -42,7 +44,13 @@ func (h *ClientHandler) CreateClient(w http.ResponseWriter, r *http.Request) {
 
 	client, err := h.service.CreateClient(ctx, clientDto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, domain.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else {
+			internalError(w, r, err, err.Error())
+		}
 		return
 	}
 
@@ -61,7 +69,7 @@ func (h *ClientHandler) CreateClient(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} domain.Client "Successfully retrieved the client details"
 // @Failure 400 "Bad request if the CPF is not provided or invalid"
 // @Failure 404 "Client not found if the CPF does not match any Client"
-// @Router /clients/{cpf} [get]
+// @Router /v1/clients/{cpf} [get]
 func (h *ClientHandler) GetClientByCPF(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	cpf := chi.URLParam(r, "cpf")
@@ -72,7 +80,11 @@ func (h *ClientHandler) GetClientByCPF(w http.ResponseWriter, r *http.Request) {
 
 	client, err := h.service.GetClientByCPF(ctx, cpf)
 	if err != nil {
-		http.Error(w, "Client not found", http.StatusNotFound)
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Client not found", http.StatusNotFound)
+		}
 		return
 	}
 
@@ -0,0 +1,54 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+type HealthHandler struct {
+	service port.HealthService
+}
+
+func NewHealthHandler(s port.HealthService) *HealthHandler {
+	return &HealthHandler{
+		service: s,
+	}
+}
+
+// Live reports that the process is up
+// @Summary Liveness probe
+// @Description Succeeds as long as the process can serve HTTP. It does not check any dependency, so a failing database never gets the container restarted.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string "The process is alive"
+// @Router /healthz [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": domain.HealthUp})
+}
+
+// Ready reports whether the API can take traffic
+// @Summary Readiness probe
+// @Description Checks the database connection, that all migrations are applied and that the background workers are running, reporting status and latency per dependency. Fails while the API is draining for shutdown.
+// @Tags health
+// @Produce json
+// @Success 200 {object} domain.HealthReport "Every dependency is up"
+// @Failure 503 {object} domain.HealthReport "A dependency is down or the API is shutting down"
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := h.service.Ready(r.Context())
+
+	status := http.StatusOK
+	if report.Status != domain.HealthUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
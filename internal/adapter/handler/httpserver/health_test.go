@@ -0,0 +1,66 @@
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/httpserver"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+)
+
+func ready(t *testing.T, handler *httpserver.HealthHandler) (int, domain.HealthReport) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report domain.HealthReport
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, report
+}
+
+func TestReadinessFailsWhileDraining(t *testing.T) {
+	health := service.NewHealthService()
+	health.Register("database", func(context.Context) error { return nil })
+	handler := httpserver.NewHealthHandler(health)
+
+	if code, report := ready(t, handler); code != http.StatusOK || report.Status != domain.HealthUp {
+		t.Fatalf("before draining: %d %s, want 200 %s", code, report.Status, domain.HealthUp)
+	}
+
+	health.Drain()
+
+	code, report := ready(t, handler)
+	if code != http.StatusServiceUnavailable || report.Status != domain.HealthDraining {
+		t.Fatalf("while draining: %d %s, want 503 %s", code, report.Status, domain.HealthDraining)
+	}
+	if check := report.Checks["database"]; check.Status != domain.HealthUp {
+		t.Errorf("database check %s while draining, want it still reported %s", check.Status, domain.HealthUp)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.Live(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("liveness %d while draining, want 200", rec.Code)
+	}
+}
+
+func TestReadinessFailsWhenADependencyIsDown(t *testing.T) {
+	health := service.NewHealthService()
+	health.Register("database", func(context.Context) error { return nil })
+	health.Register("outbox_relay", func(context.Context) error { return errors.New("not running") })
+
+	code, report := ready(t, httpserver.NewHealthHandler(health))
+	if code != http.StatusServiceUnavailable || report.Status != domain.HealthDown {
+		t.Fatalf("%d %s, want 503 %s", code, report.Status, domain.HealthDown)
+	}
+	if check := report.Checks["outbox_relay"]; check.Status != domain.HealthDown || check.Error != "not running" {
+		t.Errorf("outbox_relay check %+v, want down with its error", check)
+	}
+}
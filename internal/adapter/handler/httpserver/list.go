@@ -0,0 +1,70 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// pageParams reads the page and pageSize query parameters, defaulted and capped the
+// same way the services do, so the envelope reports the page actually returned.
+func pageParams(r *http.Request) (page, size int) {
+	page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ = strconv.Atoi(r.URL.Query().Get("pageSize"))
+	return domain.NormalizePage(page, size)
+}
+
+// writeList writes one page of items in the list envelope, with Link headers to the
+// first, previous, next and last pages.
+func writeList(w http.ResponseWriter, r *http.Request, list dto.List) {
+	last := int((list.Total + int64(list.PageSize) - 1) / int64(list.PageSize))
+	if last < 1 {
+		last = 1
+	}
+
+	link := func(page int, rel string) {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pageSize", strconv.Itoa(list.PageSize))
+		w.Header().Add("Link", fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.EscapedPath(), query.Encode(), rel))
+	}
+	link(1, "first")
+	if list.Page > 1 {
+		link(min(list.Page-1, last), "prev")
+	}
+	if list.HasNext {
+		link(list.Page+1, "next")
+	}
+	link(last, "last")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(list)
+}
+
+// writeCursorList writes one page of a cursor-paginated list, with Link headers to the
+// next and previous pages.
+func writeCursorList(w http.ResponseWriter, r *http.Request, list dto.CursorList) {
+	link := func(param, token, rel string) {
+		query := r.URL.Query()
+		query.Del("after")
+		query.Del("before")
+		query.Set(param, token)
+		query.Set("pageSize", strconv.Itoa(list.PageSize))
+		w.Header().Add("Link", fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.EscapedPath(), query.Encode(), rel))
+	}
+	if list.Prev != "" {
+		link("before", list.Prev, "prev")
+	}
+	if list.Next != "" {
+		link("after", list.Next, "next")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(list)
+}
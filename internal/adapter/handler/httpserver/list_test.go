@@ -0,0 +1,52 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
+)
+
+func TestWriteList(t *testing.T) {
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/v1/products?category=abc&page=2&pageSize=2", nil)
+	writeList(rec, r, dto.NewList([]string{"c", "d"}, 2, 2, 5))
+
+	wantLinks := []string{
+		`</v1/products?category=abc&page=1&pageSize=2>; rel="first"`,
+		`</v1/products?category=abc&page=1&pageSize=2>; rel="prev"`,
+		`</v1/products?category=abc&page=3&pageSize=2>; rel="next"`,
+		`</v1/products?category=abc&page=3&pageSize=2>; rel="last"`,
+	}
+	if links := rec.Header().Values("Link"); !reflect.DeepEqual(links, wantLinks) {
+		t.Errorf("Link = %q, want %q", links, wantLinks)
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"items": []any{"c", "d"}, "page": 2.0, "pageSize": 2.0, "total": 5.0, "hasNext": true}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want %v", body, want)
+	}
+}
+
+func TestWriteListEmpty(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeList(rec, httptest.NewRequest(http.MethodGet, "/v1/orders", nil), dto.NewList([]string(nil), 1, 10, 0))
+
+	if got, want := rec.Body.String(), `{"items":[],"page":1,"pageSize":10,"total":0,"hasNext":false}`+"\n"; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+	wantLinks := []string{
+		`</v1/orders?page=1&pageSize=10>; rel="first"`,
+		`</v1/orders?page=1&pageSize=10>; rel="last"`,
+	}
+	if links := rec.Header().Values("Link"); !reflect.DeepEqual(links, wantLinks) {
+		t.Errorf("Link = %q, want %q", links, wantLinks)
+	}
+}
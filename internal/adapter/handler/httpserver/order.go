@@ -2,21 +2,22 @@ package httpserver
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/chi"
 
 	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
-	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
 )
 
 type OrderHandler struct {
-	service *service.OrderService
+	service port.OrderService
 }
 
-func NewOrderHandler(s *service.OrderService) *OrderHandler {
+func NewOrderHandler(s port.OrderService) *OrderHandler {
 	return &OrderHandler{
 		service: s,
 	}
@@ -28,14 +29,14 @@ func NewOrderHandler(s *service.OrderService) *OrderHandler {
 // @Tags orders
 // @Accept json
 // @Produce json
-// @Param		request	body		dto.CreateOrderRequest	true	"Order creation details"
+// @Param		request	body		port.CreateOrderRequest	true	"Order creation details"
 // @Success 201 {object} domain.Order "Successfully created Order"
-// @Failure 400 "Bad request if the Order data is invalid"
+// @Failure 400 "Bad request if the Order data is invalid or a product is unavailable or discontinued"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /orders [post]
+// @Router /v1/orders [post]
 func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	var orderDto dto.CreateOrderRequest
+	var orderDto port.CreateOrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&orderDto); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -43,7 +44,11 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 
 	order, err := h.service.CreateOrder(ctx, orderDto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			internalError(w, r, err, err.Error())
+		}
 		return
 	}
 
@@ -62,7 +67,7 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} domain.Order "Successfully retrieved the order details"
 // @Failure 400 "Bad request if the ID is not provided or invalid"
 // @Failure 404 "Product not found if the ID does not match any order"
-// @Router /orders/{id} [get]
+// @Router /v1/orders/{id} [get]
 func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -84,36 +89,127 @@ func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
 
 // GetOrders retrieves a list of orders
 // @Summary List orders
-// @Description Retrieves a paginated list of orders
+// @Description Retrieves a filtered, sorted and paginated list of orders as a dto.List. With a non-empty after or before cursor, the list is paginated by cursor instead, newest first, and returned as a dto.CursorList whose next and prev tokens stay stable while new orders arrive; sort cannot be combined with them. Sorted by -created_at, the cursor order, the dto.List carries the next cursor to go on from its last order.
 // @Tags orders
 // @Accept json
 // @Produce json
+// @Param status query []int false "Statuses, repeated or comma separated" collectionFormat(csv)
+// @Param createdFrom query string false "Orders created since, a date such as 2024-05-10 or an RFC 3339 timestamp"
+// @Param createdTo query string false "Orders created until, a date included in full or an RFC 3339 timestamp excluded"
+// @Param client query string false "Client CPF"
+// @Param minTotal query number false "Minimum order total"
+// @Param maxTotal query number false "Maximum order total"
+// @Param product query string false "Product ID one of the items must reference"
+// @Param sort query string false "created_at, total or status, prefixed with - for descending order" default(created_at)
 // @Param page query int false "Page number for pagination" default(1)
-// @Param pageSize query int false "Number of orders per page" default(10)
-// @Success 200 {array} domain.Order "Successfully retrieved list of orders"
+// @Param pageSize query int false "Number of orders per page" default(10) maximum(100)
+// @Param after query string false "Cursor of the order after which the page starts, from next"
+// @Param before query string false "Cursor of the order before which the page ends, from prev"
+// @Success 200 {object} dto.List{items=[]dto.OrderSummary} "Successfully retrieved list of orders, a dto.CursorList with after or before"
+// @Failure 400 "Bad request if a filter, the sort or a cursor is invalid"
+// @Failure 404 "Client of the client filter not found if the client filter does not match any client"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /orders [get]
+// @Router /v1/orders [get]
 func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseOrderFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("after") != "" || query.Get("before") != "" {
+		if query.Has("sort") {
+			http.Error(w, "sort cannot be combined with after or before", http.StatusBadRequest)
+			return
+		}
+		h.getOrdersByCursor(w, r, filter, clientFilterNotFound)
+		return
+	}
+
+	sort, err := domain.ParseOrderSort(query.Get("sort"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
-	page, err := strconv.Atoi(r.URL.Query().Get("page"))
-	if err != nil || page < 1 {
-		page = 1
+	page, size := pageParams(r)
+
+	orders, total, err := h.service.GetOrders(ctx, filter, sort, page, size)
+	if err != nil {
+		writeOrderListError(w, r, err, clientFilterNotFound)
+		return
 	}
 
-	size, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
-	if err != nil || size <= 0 {
-		size = 10
+	list := dto.NewList(dto.NewOrderSummaries(orders), page, size, total)
+	// Sorted newest first, the list continues where the cursor pagination would.
+	if sort == (domain.OrderSort{Field: domain.OrderSortCreatedAt, Descending: true}) && list.HasNext && len(orders) > 0 {
+		list.Next = domain.NewOrderCursor(orders[len(orders)-1]).String()
 	}
+	writeList(w, r, list)
+}
 
-	orders, err := h.service.GetOrders(ctx, page, size)
+// GetClientOrders retrieves the order history of a client
+// @Summary List the orders of a client
+// @Description Retrieves the orders of a client, newest first, paginated by cursor.
+// @Tags clients
+// @Produce json
+// @Param cpf path string true "client CPF"
+// @Param status query []int false "Statuses, repeated or comma separated" collectionFormat(csv)
+// @Param createdFrom query string false "Orders created since, a date such as 2024-05-10 or an RFC 3339 timestamp"
+// @Param createdTo query string false "Orders created until, a date included in full or an RFC 3339 timestamp excluded"
+// @Param minTotal query number false "Minimum order total"
+// @Param maxTotal query number false "Maximum order total"
+// @Param product query string false "Product ID one of the items must reference"
+// @Param pageSize query int false "Number of orders per page" default(10) maximum(100)
+// @Param after query string false "Cursor of the order after which the page starts, from next"
+// @Param before query string false "Cursor of the order before which the page ends, from prev"
+// @Success 200 {object} dto.CursorList{items=[]dto.OrderSummary} "Successfully retrieved the orders of the client"
+// @Failure 400 "Bad request if the CPF, a filter or a cursor is invalid"
+// @Failure 404 "Client not found if the CPF does not match any client"
+// @Failure 500 "Internal server error if there is a problem on the server side"
+// @Router /v1/clients/{cpf}/orders [get]
+func (h *OrderHandler) GetClientOrders(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseOrderFilter(r)
+	if err == nil {
+		filter.Client, err = domain.NewCPF(chi.URLParam(r, "cpf"))
+	}
 	if err != nil {
-		http.Error(w, "Failed to retrieve orders", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(orders)
+	h.getOrdersByCursor(w, r, filter, "Client not found")
+}
+
+// clientFilterNotFound answers the order list when its client filter matches no client.
+const clientFilterNotFound = "Client of the client filter not found"
+
+func (h *OrderHandler) getOrdersByCursor(w http.ResponseWriter, r *http.Request, filter domain.OrderFilter, notFound string) {
+	_, size := pageParams(r)
+	query := r.URL.Query()
+
+	page, err := h.service.GetOrdersByCursor(r.Context(), filter, query.Get("after"), query.Get("before"), size)
+	if err != nil {
+		writeOrderListError(w, r, err, notFound)
+		return
+	}
+
+	writeCursorList(w, r, dto.NewOrderCursorList(page, size))
+}
+
+// writeOrderListError answers a failed order list; notFound is the message of a 404, which
+// differs between the routes.
+func writeOrderListError(w http.ResponseWriter, r *http.Request, err error, notFound string) {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, notFound, http.StatusNotFound)
+	default:
+		internalError(w, r, err, "Failed to retrieve orders")
+	}
 }
 
 // update order status
@@ -128,7 +224,7 @@ func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 "Bad request if the ID is not provided or invalid"
 // @Failure 400 "Bad request if the Status is not provided or invalid"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /orders/{id}/{status} [patch]
+// @Router /v1/orders/{id}/{status} [patch]
 func (h *OrderHandler) SetOrderStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -147,10 +243,10 @@ func (h *OrderHandler) SetOrderStatus(w http.ResponseWriter, r *http.Request) {
 	orderStatus, err := h.service.SetOrderStatus(ctx, id, status)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "invalid status") {
+		if errors.Is(err, domain.ErrValidation) {
 			http.Error(w, "Invalid status", http.StatusBadRequest)
 		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			internalError(w, r, err, err.Error())
 		}
 		return
 	}
@@ -170,7 +266,7 @@ func (h *OrderHandler) SetOrderStatus(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} domain.Order "Successfully fake checkout"
 // @Failure 400 "Bad request if the ID is not provided or invalid"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /fakeCheckout/{id} [post]
+// @Router /v1/fakeCheckout/{id} [post]
 func (h *OrderHandler) FakeCheckout(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -189,7 +285,7 @@ func (h *OrderHandler) FakeCheckout(w http.ResponseWriter, r *http.Request) {
 	order, err := h.service.GetOrderByID(ctx, id)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		internalError(w, r, err, err.Error())
 		return
 	}
 
@@ -0,0 +1,83 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// parseOrderFilter reads the order list filters from the query string. Statuses may be
+// repeated or comma separated. Dates are whole days in the server time zone, so
+// createdTo=2024-05-10 includes that day; timestamps are RFC 3339 and createdTo is exclusive.
+func parseOrderFilter(r *http.Request) (domain.OrderFilter, error) {
+	query := r.URL.Query()
+	var filter domain.OrderFilter
+
+	for _, values := range query["status"] {
+		for _, value := range strings.Split(values, ",") {
+			status, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return filter, fmt.Errorf("invalid status %q", value)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseFilterTime(query.Get("createdFrom"), false); err != nil {
+		return filter, fmt.Errorf("invalid createdFrom: %w", err)
+	}
+	if filter.CreatedTo, err = parseFilterTime(query.Get("createdTo"), true); err != nil {
+		return filter, fmt.Errorf("invalid createdTo: %w", err)
+	}
+
+	if value := query.Get("client"); value != "" {
+		if filter.Client, err = domain.NewCPF(value); err != nil {
+			return filter, err
+		}
+	}
+
+	if filter.MinTotal, err = parseFilterAmount(query.Get("minTotal")); err != nil {
+		return filter, fmt.Errorf("invalid minTotal: %w", err)
+	}
+	if filter.MaxTotal, err = parseFilterAmount(query.Get("maxTotal")); err != nil {
+		return filter, fmt.Errorf("invalid maxTotal: %w", err)
+	}
+
+	filter.ProductID = query.Get("product")
+	return filter, nil
+}
+
+func parseFilterTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		if end {
+			return day.AddDate(0, 0, 1), nil
+		}
+		return day, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a date such as 2024-05-10 nor an RFC 3339 timestamp", value)
+	}
+	// Orders are stamped in server time, and stores keeping timestamps as text only
+	// compare them correctly in the same offset.
+	return t.Local(), nil
+}
+
+func parseFilterAmount(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", value)
+	}
+	return &amount, nil
+}
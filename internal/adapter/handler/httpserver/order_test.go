@@ -0,0 +1,203 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+// fakeOrderService answers the order lists with orders and records which list was asked
+// for and with what. The other methods are not used by the tests.
+type fakeOrderService struct {
+	port.OrderService
+	orders []domain.Order
+	total  int64
+	page   *domain.OrderCursorPage
+	err    error
+
+	gotSort             *domain.OrderSort
+	gotFilter           domain.OrderFilter
+	gotAfter, gotBefore string
+	cursorCalled        bool
+}
+
+func (f *fakeOrderService) GetOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, page, size int) ([]domain.Order, int64, error) {
+	f.gotFilter, f.gotSort = filter, &sort
+	return f.orders, f.total, f.err
+}
+
+func (f *fakeOrderService) GetOrdersByCursor(ctx context.Context, filter domain.OrderFilter, after, before string, size int) (*domain.OrderCursorPage, error) {
+	f.gotFilter, f.gotAfter, f.gotBefore, f.cursorCalled = filter, after, before, true
+	return f.page, f.err
+}
+
+func newOrders(n int) []domain.Order {
+	orders := make([]domain.Order, n)
+	created := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	for i := range orders {
+		orders[i] = domain.Order{ID: uuid.New(), Client: "52998224725", CreatedAt: created.Add(-time.Duration(i) * time.Minute)}
+	}
+	return orders
+}
+
+func getOrders(t *testing.T, service *fakeOrderService, target string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	NewOrderHandler(service).GetOrders(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	var body map[string]any
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec, body
+}
+
+func TestGetOrdersPaginatesByCursorOnlyWithACursor(t *testing.T) {
+	orders := newOrders(2)
+	next := domain.NewOrderCursor(orders[1])
+	prev := domain.NewOrderCursor(orders[0])
+
+	for _, tc := range []struct {
+		name       string
+		target     string
+		wantCursor bool
+		wantAfter  string
+		wantBefore string
+	}{
+		{"no cursor", "/v1/orders", false, "", ""},
+		{"empty after", "/v1/orders?after=", false, "", ""},
+		{"empty before", "/v1/orders?before=", false, "", ""},
+		{"after", "/v1/orders?after=" + next.String(), true, next.String(), ""},
+		{"before", "/v1/orders?before=" + prev.String(), true, "", prev.String()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakeOrderService{
+				orders: orders,
+				total:  2,
+				page:   &domain.OrderCursorPage{Orders: orders, Next: &next, Prev: &prev},
+			}
+			rec, body := getOrders(t, service, tc.target)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			if service.cursorCalled != tc.wantCursor {
+				t.Fatalf("paginated by cursor = %t, want %t", service.cursorCalled, tc.wantCursor)
+			}
+			if service.gotAfter != tc.wantAfter || service.gotBefore != tc.wantBefore {
+				t.Errorf("the service got after %q and before %q, want %q and %q", service.gotAfter, service.gotBefore, tc.wantAfter, tc.wantBefore)
+			}
+
+			_, hasPage := body["page"]
+			_, hasTotal := body["total"]
+			if tc.wantCursor {
+				if hasPage || hasTotal || body["next"] != next.String() || body["prev"] != prev.String() {
+					t.Errorf("body = %v, want a cursor list with next and prev", body)
+				}
+			} else if !hasPage || !hasTotal {
+				t.Errorf("body = %v, want a list with page and total", body)
+			}
+		})
+	}
+}
+
+func TestGetOrdersRejectsSortWithACursor(t *testing.T) {
+	next := domain.NewOrderCursor(newOrders(1)[0])
+	service := &fakeOrderService{}
+	rec, _ := getOrders(t, service, "/v1/orders?sort=-total&after="+next.String())
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if service.cursorCalled || service.gotSort != nil {
+		t.Error("the service was called")
+	}
+}
+
+func TestGetOrdersNewestFirstCarriesTheNextCursor(t *testing.T) {
+	orders := newOrders(2)
+
+	for _, tc := range []struct {
+		name     string
+		target   string
+		total    int64
+		wantNext string
+	}{
+		{"newest first", "/v1/orders?sort=-created_at&pageSize=2", 5, domain.NewOrderCursor(orders[1]).String()},
+		{"last page", "/v1/orders?sort=-created_at&pageSize=2", 2, ""},
+		{"other sort", "/v1/orders?sort=created_at&pageSize=2", 5, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, body := getOrders(t, &fakeOrderService{orders: orders, total: tc.total}, tc.target)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			next, _ := body["next"].(string)
+			if next != tc.wantNext {
+				t.Errorf("next = %q, want %q", next, tc.wantNext)
+			}
+		})
+	}
+}
+
+func TestGetClientOrders(t *testing.T) {
+	orders := newOrders(1)
+	prev := domain.NewOrderCursor(orders[0])
+	service := &fakeOrderService{page: &domain.OrderCursorPage{Orders: orders, Prev: &prev}}
+
+	r := chi.NewRouter()
+	r.Get("/v1/clients/{cpf}/orders", NewOrderHandler(service).GetClientOrders)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/clients/529.982.247-25/orders?after=", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if !service.cursorCalled || service.gotFilter.Client != "52998224725" {
+		t.Errorf("the service got client %q by cursor %t, want the first page of 52998224725", service.gotFilter.Client, service.cursorCalled)
+	}
+	if link := rec.Header().Get("Link"); link != `</v1/clients/529.982.247-25/orders?before=`+prev.String()+`&pageSize=10>; rel="prev"` {
+		t.Errorf("Link = %q", link)
+	}
+}
+
+func TestOrderListsNameWhatWasNotFound(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"client orders", "/v1/clients/52998224725/orders", "Client not found"},
+		{"client filter", "/v1/orders?client=52998224725", clientFilterNotFound},
+		{"client filter by cursor", "/v1/orders?client=52998224725&after=" + domain.NewOrderCursor(newOrders(1)[0]).String(), clientFilterNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewOrderHandler(&fakeOrderService{err: domain.ErrNotFound})
+			r := chi.NewRouter()
+			r.Get("/v1/orders", handler.GetOrders)
+			r.Get("/v1/clients/{cpf}/orders", handler.GetClientOrders)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tc.want {
+				t.Errorf("body = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
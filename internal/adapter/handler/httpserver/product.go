@@ -2,21 +2,22 @@ package httpserver
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi"
 
 	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
-	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
 )
 
 type ProductHandler struct {
-	service *service.ProductService
+	service port.ProductService
 }
 
-func NewProductHandler(s *service.ProductService) *ProductHandler {
+func NewProductHandler(s port.ProductService) *ProductHandler {
 	return &ProductHandler{
 		service: s,
 	}
@@ -28,14 +29,14 @@ func NewProductHandler(s *service.ProductService) *ProductHandler {
 // @Tags products
 // @Accept json
 // @Produce json
-// @Param		request	body		dto.CreateProductRequest	true	"Product creation details"
+// @Param		request	body		port.CreateProductRequest	true	"Product creation details"
 // @Success 201 {object} domain.Product "Product successfully created"
 // @Failure 400 "Bad request if the product data is invalid"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /products [post]
+// @Router /v1/products [post]
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	var productDto dto.CreateProductRequest
+	var productDto port.CreateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&productDto); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -43,7 +44,11 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 
 	product, err := h.service.CreateProduct(ctx, productDto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			internalError(w, r, err, err.Error())
+		}
 		return
 	}
 
@@ -59,12 +64,12 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
-// @Param		request	body		dto.CreateProductRequest	true	"Product object that needs to be replaced"
+// @Param		request	body		port.CreateProductRequest	true	"Product object that needs to be replaced"
 // @Success 200 {object} domain.Product "Product successfully updated"
 // @Failure 400 {string} string "Invalid input, Object is invalid"
 // @Failure 404 {string} string "Product not found"
 // @Failure 500 {string} string "Internal server error"
-// @Router /products/{id} [patch]
+// @Router /v1/products/{id} [patch]
 func (h *ProductHandler) ReplaceProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -73,7 +78,7 @@ func (h *ProductHandler) ReplaceProduct(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var productDto dto.CreateProductRequest
+	var productDto port.CreateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&productDto); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -82,10 +87,12 @@ func (h *ProductHandler) ReplaceProduct(w http.ResponseWriter, r *http.Request)
 	product, err := h.service.ReplaceProduct(ctx, id, productDto)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if strings.Contains(err.Error(), "not found") {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
-			http.Error(w, "Error updating product", http.StatusInternalServerError)
+			internalError(w, r, err, "Error updating product")
 		}
 		return
 	}
@@ -102,12 +109,12 @@ func (h *ProductHandler) ReplaceProduct(w http.ResponseWriter, r *http.Request)
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
-// @Param		request	body		dto.CreateProductRequest	true	"Product object that needs to be updated"
+// @Param		request	body		port.CreateProductRequest	true	"Product object that needs to be updated"
 // @Success 200 {object} domain.Product "Product successfully updated"
 // @Failure 400 {string} string "Invalid input, Object is invalid"
 // @Failure 404 {string} string "Product not found"
 // @Failure 500 {string} string "Internal server error"
-// @Router /products/{id} [put]
+// @Router /v1/products/{id} [put]
 func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -116,7 +123,7 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var productDto dto.CreateProductRequest
+	var productDto port.CreateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&productDto); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -125,10 +132,12 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	product, err := h.service.UpdateProduct(ctx, id, productDto)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if strings.Contains(err.Error(), "not found") {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
-			http.Error(w, "Error updating product", http.StatusInternalServerError)
+			internalError(w, r, err, "Error updating product")
 		}
 		return
 	}
@@ -148,7 +157,7 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} domain.Product "Successfully retrieved the product details"
 // @Failure 400 "Bad request if the ID is not provided or invalid"
 // @Failure 404 "Product not found if the ID does not match any product"
-// @Router /products/{id} [get]
+// @Router /v1/products/{id} [get]
 func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -162,7 +171,7 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Product not found", http.StatusNotFound)
 		} else {
-			http.Error(w, "Error retrieving product", http.StatusInternalServerError)
+			internalError(w, r, err, "Error retrieving product")
 		}
 		return
 	}
@@ -174,39 +183,93 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 
 // GetProducts retrieves a list of products
 // @Summary List products
-// @Description Retrieves a paginated list of products optionally filtered by category.
+// @Description Retrieves a filtered, sorted and paginated list of products, e.g. for the kiosk search box. Unavailable and discontinued products are left out unless includeUnavailable is set.
 // @Tags products
 // @Accept json
 // @Produce json
-// @Param category query string false "Filter products by category"
+// @Param includeUnavailable query bool false "Also list the products that cannot be ordered now, hidden from kiosks by default" default(false)
+// @Param search query string false "Words to look for in the name and description, ignoring case and accents"
+// @Param category query []string false "Category IDs, repeated or comma separated" collectionFormat(csv)
+// @Param minPrice query number false "Minimum price"
+// @Param maxPrice query number false "Maximum price"
+// @Param sort query string false "name, price, popularity or created_at, prefixed with - for descending order" default(created_at)
 // @Param page query int false "Page number for pagination" default(1)
-// @Param pageSize query int false "Number of products per page" default(10)
-// @Success 200 {array} domain.Product "Successfully retrieved list of products"
+// @Param pageSize query int false "Number of products per page" default(10) maximum(100)
+// @Success 200 {object} dto.List{items=[]domain.Product} "Successfully retrieved list of products"
+// @Failure 400 "Bad request if a filter or the sort is invalid"
+// @Failure 404 "Category not found if a category filter does not match any category"
 // @Failure 500 "Internal server error if there is a problem on the server side"
-// @Router /products [get]
+// @Router /v1/products [get]
 func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	page, err := strconv.Atoi(r.URL.Query().Get("page"))
-	if err != nil || page < 1 {
-		page = 1
+	page, size := pageParams(r)
+
+	filter, err := parseProductFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sort, err := domain.ParseProductSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	size, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
-	if err != nil || size <= 0 {
-		size = 10
+	products, total, err := h.service.GetProducts(ctx, filter, sort, page, size)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrValidation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrNotFound):
+			http.Error(w, "Category not found", http.StatusNotFound)
+		default:
+			internalError(w, r, err, err.Error())
+		}
+		return
 	}
 
-	category := r.URL.Query().Get("category")
+	writeList(w, r, dto.NewList(products, page, size, total))
+}
+
+// SetAvailability changes whether a product can be ordered
+// @Summary Set the availability of a product
+// @Description Lets the kitchen take a product off the kiosks while it is out of stock, optionally until a known time, discontinue it, or make it available again. Unavailable products cannot be ordered.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body port.SetAvailabilityRequest true "New availability"
+// @Success 200 {object} domain.Product "Availability successfully changed"
+// @Failure 400 "Bad request if the ID or the availability is invalid"
+// @Failure 404 "Product not found if the ID does not match any product"
+// @Failure 500 "Internal server error if there is a problem on the server side"
+// @Router /v1/products/{id}/availability [post]
+func (h *ProductHandler) SetAvailability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
 
-	products, err := h.service.GetProducts(ctx, category, page, size)
+	var request port.SetAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.service.SetAvailability(ctx, id, request)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, domain.ErrValidation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrNotFound):
+			http.Error(w, "Product not found", http.StatusNotFound)
+		default:
+			internalError(w, r, err, "Error changing product availability")
+		}
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(products)
+	json.NewEncoder(w).Encode(product)
 }
 
 // DeleteProduct deletes a product by its ID
@@ -220,7 +283,7 @@ func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 "Bad request if the ID is not provided or is invalid"
 // @Failure 404 "Product not found if the ID does not match any product"
 // @Failure 500 "Internal server error if there is a problem deleting the product"
-// @Router /products/{id} [delete]
+// @Router /v1/products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
@@ -234,7 +297,7 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Product not found", http.StatusNotFound)
 		} else {
-			http.Error(w, "Error deleting product", http.StatusInternalServerError)
+			internalError(w, r, err, "Error deleting product")
 		}
 		return
 	}
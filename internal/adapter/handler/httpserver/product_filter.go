@@ -0,0 +1,51 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// parseProductFilter reads the product list filters from the query string. Categories
+// may be repeated or comma separated. Products that cannot be ordered now are left out,
+// as kiosks list them, unless includeUnavailable is set.
+func parseProductFilter(r *http.Request) (domain.ProductFilter, error) {
+	query := r.URL.Query()
+	filter := domain.ProductFilter{Search: strings.TrimSpace(query.Get("search"))}
+
+	includeUnavailable := false
+	if value := query.Get("includeUnavailable"); value != "" {
+		var err error
+		if includeUnavailable, err = strconv.ParseBool(value); err != nil {
+			return filter, fmt.Errorf("invalid includeUnavailable %q", value)
+		}
+	}
+	if !includeUnavailable {
+		filter.AvailableAt = time.Now()
+	}
+
+	for _, values := range query["category"] {
+		for _, value := range strings.Split(values, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(value))
+			if err != nil {
+				return filter, fmt.Errorf("invalid category %q", value)
+			}
+			filter.CategoryIDs = append(filter.CategoryIDs, id)
+		}
+	}
+
+	var err error
+	if filter.MinPrice, err = parseFilterAmount(query.Get("minPrice")); err != nil {
+		return filter, fmt.Errorf("invalid minPrice: %w", err)
+	}
+	if filter.MaxPrice, err = parseFilterAmount(query.Get("maxPrice")); err != nil {
+		return filter, fmt.Errorf("invalid maxPrice: %w", err)
+	}
+	return filter, nil
+}
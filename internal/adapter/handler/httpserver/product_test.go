@@ -0,0 +1,88 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+// fakeProductService changes the availability of product and records what it was asked.
+// The other methods are not used by the tests.
+type fakeProductService struct {
+	port.ProductService
+	product *domain.Product
+	err     error
+
+	gotID      string
+	gotRequest *port.SetAvailabilityRequest
+}
+
+func (f *fakeProductService) SetAvailability(ctx context.Context, id string, request port.SetAvailabilityRequest) (*domain.Product, error) {
+	f.gotID, f.gotRequest = id, &request
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.product.Availability = domain.Availability{Status: request.Status, BackAt: request.BackAt}
+	return f.product, nil
+}
+
+func TestSetAvailability(t *testing.T) {
+	id := uuid.New()
+	backAt := time.Date(2026, time.October, 20, 11, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"unavailable until", `{"status":"unavailable","back_at":"2026-10-20T11:00:00Z"}`, nil, http.StatusOK, ""},
+		{"invalid availability", `{"status":"sold out"}`, fmt.Errorf("%w: invalid availability status", domain.ErrValidation), http.StatusBadRequest, "validation failed: invalid availability status"},
+		{"unknown product", `{"status":"unavailable"}`, domain.ErrNotFound, http.StatusNotFound, "Product not found"},
+		{"storage failure", `{"status":"unavailable"}`, errors.New("connection reset"), http.StatusInternalServerError, "Error changing product availability"},
+		{"malformed body", `{"status":`, nil, http.StatusBadRequest, "Invalid request body"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakeProductService{product: &domain.Product{ID: id, Name: "X-Burger"}, err: tc.err}
+			r := chi.NewRouter()
+			r.Post("/v1/products/{id}/availability", NewProductHandler(service).SetAvailability)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/products/"+id.String()+"/availability", strings.NewReader(tc.body)))
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.wantStatus, rec.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				if got := strings.TrimSpace(rec.Body.String()); got != tc.wantBody {
+					t.Errorf("body = %q, want %q", got, tc.wantBody)
+				}
+				return
+			}
+
+			if service.gotID != id.String() || service.gotRequest.Status != domain.AvailabilityUnavailable ||
+				service.gotRequest.BackAt == nil || !service.gotRequest.BackAt.Equal(backAt) {
+				t.Errorf("the service got product %s and %+v, want %s unavailable until %s", service.gotID, service.gotRequest, id, backAt)
+			}
+			var product domain.Product
+			if err := json.NewDecoder(rec.Body).Decode(&product); err != nil {
+				t.Fatal(err)
+			}
+			if product.ID != id || product.Availability.Status != domain.AvailabilityUnavailable {
+				t.Errorf("product = %+v, want %s unavailable", product, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// Handlers groups the resource handlers shared by every API version. A new version
+// mounts its own routes next to V1, reusing these handlers, or new ones built on the
+// same services, for the resources it does not change.
+type Handlers struct {
+	Categories *CategoryHandler
+	Products   *ProductHandler
+	Clients    *ClientHandler
+	Orders     *OrderHandler
+	Webhooks   *WebhookHandler
+	// AdminToken guards the /webhooks admin routes, which are not mounted while it is empty.
+	AdminToken string
+}
+
+// V1 registers the version 1 resource routes on r, which is mounted on /v1.
+//
+// @title			Skina Lanches Management API
+// @version		1.0
+// @description	APIs for using the management system and sales orders
+// @BasePath					/
+func (h Handlers) V1(r chi.Router) {
+	r.Route("/products", func(r chi.Router) {
+		r.Post("/", h.Products.CreateProduct)
+		r.Put("/{id}", h.Products.ReplaceProduct)
+		r.Patch("/{id}", h.Products.UpdateProduct)
+		r.Get("/{id}", h.Products.GetProductByID)
+		r.Get("/", h.Products.GetProducts)
+		r.Delete("/{id}", h.Products.DeleteProduct)
+		r.Post("/{id}/availability", h.Products.SetAvailability)
+	})
+
+	r.Route("/categories", func(r chi.Router) {
+		r.Post("/", h.Categories.CreateCategory)
+		r.Patch("/{id}", h.Categories.UpdateCategory)
+		r.Put("/{id}", h.Categories.ReplaceCategory)
+		r.Get("/{id}", h.Categories.GetCategoryByID)
+		r.Get("/", h.Categories.GetCategories)
+		r.Delete("/{id}", h.Categories.DeleteCategory)
+	})
+
+	r.Route("/clients", func(r chi.Router) {
+		r.Post("/", h.Clients.CreateClient)
+		r.Get("/{cpf}", h.Clients.GetClientByCPF)
+		r.Get("/{cpf}/orders", h.Orders.GetClientOrders)
+	})
+
+	r.Route("/orders", func(r chi.Router) {
+		r.Get("/", h.Orders.GetOrders)
+		r.Get("/{id}", h.Orders.GetOrderByID)
+		r.Post("/", h.Orders.CreateOrder)
+		r.Patch("/{id}/{status}", h.Orders.SetOrderStatus)
+	})
+
+	r.Route("/fakeCheckout", func(r chi.Router) {
+		r.Post("/{id}", h.Orders.FakeCheckout)
+	})
+
+	if h.AdminToken == "" {
+		return
+	}
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Use(RequireBearerToken(h.AdminToken))
+		r.Post("/subscriptions", h.Webhooks.CreateSubscription)
+		r.Get("/subscriptions", h.Webhooks.GetSubscriptions)
+		r.Get("/subscriptions/{id}", h.Webhooks.GetSubscriptionByID)
+		r.Put("/subscriptions/{id}", h.Webhooks.ReplaceSubscription)
+		r.Delete("/subscriptions/{id}", h.Webhooks.DeleteSubscription)
+		r.Get("/subscriptions/{id}/deliveries", h.Webhooks.GetDeliveries)
+		r.Get("/deliveries/{id}", h.Webhooks.GetDeliveryByID)
+		r.Post("/deliveries/{id}/redeliver", h.Webhooks.Redeliver)
+	})
+}
+
+// Deprecated marks the routes it wraps as deprecated aliases of the same routes under
+// successor, e.g. "/v1": responses carry the Deprecation (RFC 9745) and Sunset (RFC 8594)
+// headers and a Link to the successor version of the requested path.
+func Deprecated(since, sunset time.Time, successor string) func(http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", since.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunsetDate)
+			w.Header().Add("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successor, r.URL.EscapedPath()))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
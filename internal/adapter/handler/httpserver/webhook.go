@@ -0,0 +1,269 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+type WebhookHandler struct {
+	service port.WebhookService
+}
+
+func NewWebhookHandler(s port.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: s,
+	}
+}
+
+// CreateSubscription registers a partner endpoint to receive events
+// @Summary Add a webhook subscription
+// @Description Registers a URL to receive the given event types ("*" for all), signed with the given secret.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param		request	body		port.CreateWebhookSubscriptionRequest	true	"Subscription details"
+// @Param Authorization header string true "Bearer followed by ADMIN_TOKEN"
+// @Success 201 {object} domain.WebhookSubscription "Subscription successfully created"
+// @Failure 400 "Bad request if the subscription data is invalid"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 500 "Internal server error if there is a problem on the server side"
+// @Router /v1/webhooks/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var subscriptionDto port.CreateWebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&subscriptionDto); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(ctx, subscriptionDto)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			internalError(w, r, err, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// ReplaceSubscription replaces a webhook subscription
+// @Summary Replace a webhook subscription
+// @Description Replaces URL, event types and secret of a subscription by ID.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param		request	body		port.CreateWebhookSubscriptionRequest	true	"Subscription details"
+// @Param Authorization header string true "Bearer followed by ADMIN_TOKEN"
+// @Success 200 {object} domain.WebhookSubscription "Subscription successfully replaced"
+// @Failure 400 {string} string "Invalid input, Object is invalid"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/webhooks/subscriptions/{id} [put]
+func (h *WebhookHandler) ReplaceSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	var subscriptionDto port.CreateWebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&subscriptionDto); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.service.ReplaceSubscription(ctx, id, subscriptionDto)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Subscription not found", http.StatusNotFound)
+		} else {
+			internalError(w, r, err, "Error replacing subscription")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// GetSubscriptionByID retrieves a webhook subscription by its ID
+// @Summary Get a webhook subscription
+// @Description Retrieves a subscription based on its unique ID. The secret is never returned.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param Authorization header string true "Bearer followed by ADMIN_TOKEN"
+// @Success 200 {object} domain.WebhookSubscription "Successfully retrieved the subscription"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 404 "Subscription not found if the ID does not match any subscription"
+// @Router /v1/webhooks/subscriptions/{id} [get]
+func (h *WebhookHandler) GetSubscriptionByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	subscription, err := h.service.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		http.Error(w, "Subscription not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// GetSubscriptions retrieves a list of webhook subscriptions
+// @Summary List webhook subscriptions
+// @Description Retrieves a paginated list of subscriptions
+// @Tags webhooks
+// @Produce json
+// @Param page query int false "Page number for pagination" default(1)
+// @Param pageSize query int false "Number of subscriptions per page" default(10) maximum(100)
+// @Param Authorization header string true "Bearer followed by ADMIN_TOKEN"
+// @Success 200 {object} dto.List{items=[]domain.WebhookSubscription} "Successfully retrieved list of subscriptions"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 500 "Internal server error if there is a problem on the server side"
+// @Router /v1/webhooks/subscriptions [get]
+func (h *WebhookHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	page, size := pageParams(r)
+
+	subscriptions, total, err := h.service.GetSubscriptions(ctx, page, size)
+	if err != nil {
+		internalError(w, r, err, "Failed to retrieve subscriptions")
+		return
+	}
+
+	writeList(w, r, dto.NewList(subscriptions, page, size, total))
+}
+
+// DeleteSubscription deletes a webhook subscription by its ID
+// @Summary Delete a webhook subscription
+// @Description Deletes a subscription; its pending deliveries are given up.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param Authorization header string true "Bearer followed by ADMIN_TOKEN"
+// @Success 200 {object} map[string]string "Message indicating successful deletion"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 404 "Subscription not found if the ID does not match any subscription"
+// @Router /v1/webhooks/subscriptions/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.DeleteSubscription(ctx, id); err != nil {
+		http.Error(w, "Subscription not found or error deleting subscription", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := map[string]string{"message": "Subscription with ID " + id + " deleted successfully."}
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetDeliveries retrieves the delivery log of a webhook subscription
+// @Summary List webhook deliveries
+// @Description Retrieves the deliveries of a subscription, most recent first, with the response code of every attempt.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param pageSize query int false "Number of deliveries per page" default(10) maximum(100)
+// @Param Authorization header string true "Bearer followed by ADMIN_TOKEN"
+// @Success 200 {object} dto.List{items=[]domain.WebhookDelivery} "Successfully retrieved list of deliveries"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 404 "Subscription not found if the ID does not match any subscription"
+// @Failure 500 "Internal server error if there is a problem on the server side"
+// @Router /v1/webhooks/subscriptions/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	page, size := pageParams(r)
+
+	deliveries, total, err := h.service.GetDeliveries(ctx, id, page, size)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Subscription not found", http.StatusNotFound)
+		} else {
+			internalError(w, r, err, "Failed to retrieve deliveries")
+		}
+		return
+	}
+
+	writeList(w, r, dto.NewList(deliveries, page, size, total))
+}
+
+// GetDeliveryByID retrieves a webhook delivery by its ID
+// @Summary Get a webhook delivery
+// @Description Retrieves a delivery with its attempt log.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Param Authorization header string true "Bearer followed by ADMIN_TOKEN"
+// @Success 200 {object} domain.WebhookDelivery "Successfully retrieved the delivery"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 404 "Delivery not found if the ID does not match any delivery"
+// @Router /v1/webhooks/deliveries/{id} [get]
+func (h *WebhookHandler) GetDeliveryByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	delivery, err := h.service.GetDeliveryByID(ctx, id)
+	if err != nil {
+		http.Error(w, "Delivery not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// Redeliver schedules a webhook delivery to be sent again
+// @Summary Redeliver a webhook
+// @Description Schedules a delivery to be attempted again right away, whatever its current status.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Param Authorization header string true "Bearer followed by ADMIN_TOKEN"
+// @Success 202 {object} domain.WebhookDelivery "Redelivery scheduled"
+// @Failure 401 "Unauthorized if the bearer token is missing or wrong"
+// @Failure 404 "Delivery not found if the ID does not match any delivery"
+// @Failure 500 "Internal server error if there is a problem on the server side"
+// @Router /v1/webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	delivery, err := h.service.Redeliver(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Delivery not found", http.StatusNotFound)
+		} else {
+			internalError(w, r, err, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
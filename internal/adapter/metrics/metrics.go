@@ -0,0 +1,198 @@
+// Package metrics records HTTP, repository and business metrics and exposes them
+// in the Prometheus text format.
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+)
+
+const (
+	namespace = "skinaapis"
+
+	// ordersScrapeTimeout bounds the query counting orders by status on every scrape.
+	ordersScrapeTimeout = 5 * time.Second
+
+	maxTrackedOrders = 10_000
+	trackedOrderTTL  = 24 * time.Hour
+)
+
+// Metrics owns the registry served on /metrics. It implements port.EventPublisher so the
+// outbox relay feeds it the business events; Publish never fails, so a broken metric never
+// holds back event delivery.
+type Metrics struct {
+	registry           *prometheus.Registry
+	orders             port.OrderRepository
+	httpRequests       *prometheus.CounterVec
+	httpDuration       *prometheus.HistogramVec
+	repositoryDuration *prometheus.HistogramVec
+	ordersCreated      prometheus.Counter
+	revenue            prometheus.Counter
+	timeToReady        prometheus.Histogram
+	ordersByStatus     *prometheus.Desc
+
+	mu         sync.Mutex
+	receivedAt map[uuid.UUID]time.Time
+}
+
+var _ port.EventPublisher = (*Metrics)(nil)
+
+// New registers the API metrics; orders is queried on every scrape for the orders by status gauge.
+func New(orders port.OrderRepository) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		orders:   orders,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, chi route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, chi route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Duration of repository calls by repository, method and outcome (ok or error).",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method", "outcome"}),
+		ordersCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_created_total",
+			Help:      "Orders created.",
+		}),
+		revenue: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_revenue_total",
+			Help:      "Sum of the totals of the created orders, in BRL.",
+		}),
+		timeToReady: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "order_time_to_ready_seconds",
+			Help:      "Time from an order being received (checked out) until it is ready.",
+			Buckets:   prometheus.ExponentialBuckets(60, 2, 8),
+		}),
+		ordersByStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "orders"),
+			"Orders currently in each status.",
+			[]string{"status"}, nil,
+		),
+		receivedAt: map[uuid.UUID]time.Time{},
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.repositoryDuration,
+		m.ordersCreated,
+		m.revenue,
+		m.timeToReady,
+		orderStatusCollector{m},
+	)
+
+	return m
+}
+
+// Handler serves the registered metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Publish records the business metrics carried by event. Events may be delivered more
+// than once, so the counters can slightly overcount after a retried delivery.
+func (m *Metrics) Publish(ctx context.Context, event domain.Event) error {
+	switch event.Type {
+	case domain.EventOrderCreated:
+		var payload domain.OrderCreated
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			logging.FromContext(ctx).Warn("metrics could not decode event", "event_type", event.Type, "event_id", event.ID, "error", err)
+			return nil
+		}
+		m.ordersCreated.Inc()
+		m.revenue.Add(payload.Total)
+	case domain.EventOrderStatusChanged:
+		var payload domain.OrderStatusChanged
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			logging.FromContext(ctx).Warn("metrics could not decode event", "event_type", event.Type, "event_id", event.ID, "error", err)
+			return nil
+		}
+		m.observeStatusChange(payload.OrderID, payload.Status, event.OccurredAt)
+	}
+	return nil
+}
+
+// observeStatusChange remembers when an order was received and observes the time to ready
+// once it gets there. Orders received before a restart or relayed by another replica are skipped.
+func (m *Metrics) observeStatusChange(orderID uuid.UUID, status int, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch status {
+	case domain.OrderStatusReceived:
+		if len(m.receivedAt) >= maxTrackedOrders {
+			m.forgetStale(at)
+		}
+		m.receivedAt[orderID] = at
+	case domain.OrderStatusReady:
+		if receivedAt, ok := m.receivedAt[orderID]; ok {
+			m.timeToReady.Observe(at.Sub(receivedAt).Seconds())
+		}
+		delete(m.receivedAt, orderID)
+	case domain.OrderStatusFinished:
+		delete(m.receivedAt, orderID)
+	}
+}
+
+// forgetStale drops the orders received more than trackedOrderTTL before now, which
+// were most likely abandoned, so orders that never get ready do not pile up.
+func (m *Metrics) forgetStale(now time.Time) {
+	for id, receivedAt := range m.receivedAt {
+		if now.Sub(receivedAt) > trackedOrderTTL {
+			delete(m.receivedAt, id)
+		}
+	}
+}
+
+// orderStatusCollector reports the orders by status gauge from the repository at scrape time,
+// so the value is right no matter which replica changed the orders.
+type orderStatusCollector struct {
+	m *Metrics
+}
+
+func (c orderStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.m.ordersByStatus
+}
+
+func (c orderStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), ordersScrapeTimeout)
+	defer cancel()
+
+	counts, err := c.m.orders.CountByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.m.ordersByStatus, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.m.ordersByStatus, prometheus.GaugeValue, float64(counts[domain.OrderStatusCreated]), "created")
+	for status := domain.OrderStatusReceived; status <= domain.OrderStatusFinished; status++ {
+		orderStatus, _ := domain.SetStatus(status)
+		ch <- prometheus.MustNewConstMetric(c.m.ordersByStatus, prometheus.GaugeValue, float64(counts[status]), orderStatus.StatusDescription)
+	}
+}
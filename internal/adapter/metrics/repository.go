@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+// The Instrument methods wrap a repository port so every call is observed in
+// repository_operation_duration_seconds; timer does the observing for one repository.
+type timer struct {
+	m          *Metrics
+	repository string
+}
+
+func (t timer) observe(method string, start time.Time, err *error) {
+	outcome := "ok"
+	if *err != nil {
+		outcome = "error"
+	}
+	t.m.repositoryDuration.WithLabelValues(t.repository, method, outcome).Observe(time.Since(start).Seconds())
+}
+
+type categoryRepository struct {
+	next port.CategoryRepository
+	timer
+}
+
+func (m *Metrics) InstrumentCategories(repo port.CategoryRepository) port.CategoryRepository {
+	return categoryRepository{next: repo, timer: timer{m: m, repository: "categories"}}
+}
+
+func (r categoryRepository) CreateCategory(ctx context.Context, category *domain.Category) (_ *domain.Category, err error) {
+	defer r.observe("CreateCategory", time.Now(), &err)
+	return r.next.CreateCategory(ctx, category)
+}
+
+func (r categoryRepository) GetCategoryByID(ctx context.Context, id string) (_ *domain.Category, err error) {
+	defer r.observe("GetCategoryByID", time.Now(), &err)
+	return r.next.GetCategoryByID(ctx, id)
+}
+
+func (r categoryRepository) GetCategories(ctx context.Context, page, limit int) (_ []domain.Category, _ int64, err error) {
+	defer r.observe("GetCategories", time.Now(), &err)
+	return r.next.GetCategories(ctx, page, limit)
+}
+
+func (r categoryRepository) ReplaceCategory(ctx context.Context, category *domain.Category) (_ *domain.Category, err error) {
+	defer r.observe("ReplaceCategory", time.Now(), &err)
+	return r.next.ReplaceCategory(ctx, category)
+}
+
+func (r categoryRepository) DeleteCategory(ctx context.Context, id string) (err error) {
+	defer r.observe("DeleteCategory", time.Now(), &err)
+	return r.next.DeleteCategory(ctx, id)
+}
+
+func (r categoryRepository) UpdateCategory(ctx context.Context, category *domain.Category) (_ *domain.Category, err error) {
+	defer r.observe("UpdateCategory", time.Now(), &err)
+	return r.next.UpdateCategory(ctx, category)
+}
+
+type productRepository struct {
+	next port.ProductRepository
+	timer
+}
+
+func (m *Metrics) InstrumentProducts(repo port.ProductRepository) port.ProductRepository {
+	return productRepository{next: repo, timer: timer{m: m, repository: "products"}}
+}
+
+func (r productRepository) CreateProduct(ctx context.Context, product *domain.Product) (_ *domain.Product, err error) {
+	defer r.observe("CreateProduct", time.Now(), &err)
+	return r.next.CreateProduct(ctx, product)
+}
+
+func (r productRepository) GetProductByID(ctx context.Context, id string) (_ *domain.Product, err error) {
+	defer r.observe("GetProductByID", time.Now(), &err)
+	return r.next.GetProductByID(ctx, id)
+}
+
+func (r productRepository) GetProducts(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, page, limit int) (_ []domain.Product, _ int64, err error) {
+	defer r.observe("GetProducts", time.Now(), &err)
+	return r.next.GetProducts(ctx, filter, sort, page, limit)
+}
+
+func (r productRepository) ReplaceProduct(ctx context.Context, product *domain.Product) (_ *domain.Product, err error) {
+	defer r.observe("ReplaceProduct", time.Now(), &err)
+	return r.next.ReplaceProduct(ctx, product)
+}
+
+func (r productRepository) UpdateProduct(ctx context.Context, product *domain.Product) (_ *domain.Product, err error) {
+	defer r.observe("UpdateProduct", time.Now(), &err)
+	return r.next.UpdateProduct(ctx, product)
+}
+
+func (r productRepository) DeleteProduct(ctx context.Context, id string) (err error) {
+	defer r.observe("DeleteProduct", time.Now(), &err)
+	return r.next.DeleteProduct(ctx, id)
+}
+
+func (r productRepository) SetAvailability(ctx context.Context, id uuid.UUID, availability domain.Availability) (err error) {
+	defer r.observe("SetAvailability", time.Now(), &err)
+	return r.next.SetAvailability(ctx, id, availability)
+}
+
+func (r productRepository) RecordSales(ctx context.Context, items []domain.OrderItem) (err error) {
+	defer r.observe("RecordSales", time.Now(), &err)
+	return r.next.RecordSales(ctx, items)
+}
+
+type clientRepository struct {
+	next port.ClientRepository
+	timer
+}
+
+func (m *Metrics) InstrumentClients(repo port.ClientRepository) port.ClientRepository {
+	return clientRepository{next: repo, timer: timer{m: m, repository: "clients"}}
+}
+
+func (r clientRepository) CreateClient(ctx context.Context, client *domain.Client) (_ *domain.Client, err error) {
+	defer r.observe("CreateClient", time.Now(), &err)
+	return r.next.CreateClient(ctx, client)
+}
+
+func (r clientRepository) GetClientByCPF(ctx context.Context, cpf domain.CPF) (_ *domain.Client, err error) {
+	defer r.observe("GetClientByCPF", time.Now(), &err)
+	return r.next.GetClientByCPF(ctx, cpf)
+}
+
+type orderRepository struct {
+	next port.OrderRepository
+	timer
+}
+
+func (m *Metrics) InstrumentOrders(repo port.OrderRepository) port.OrderRepository {
+	return orderRepository{next: repo, timer: timer{m: m, repository: "orders"}}
+}
+
+func (r orderRepository) CreateOrder(ctx context.Context, order *domain.Order) (_ *domain.Order, err error) {
+	defer r.observe("CreateOrder", time.Now(), &err)
+	return r.next.CreateOrder(ctx, order)
+}
+
+func (r orderRepository) GetOrderByID(ctx context.Context, id string) (_ *domain.Order, err error) {
+	defer r.observe("GetOrderByID", time.Now(), &err)
+	return r.next.GetOrderByID(ctx, id)
+}
+
+func (r orderRepository) GetOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, page, pageSize int) (_ []domain.Order, _ int64, err error) {
+	defer r.observe("GetOrders", time.Now(), &err)
+	return r.next.GetOrders(ctx, filter, sort, page, pageSize)
+}
+
+func (r orderRepository) GetOrdersByCursor(ctx context.Context, query domain.OrderCursorQuery) (_ []domain.Order, err error) {
+	defer r.observe("GetOrdersByCursor", time.Now(), &err)
+	return r.next.GetOrdersByCursor(ctx, query)
+}
+
+func (r orderRepository) SetStatus(ctx context.Context, id uuid.UUID, status int, description string) (err error) {
+	defer r.observe("SetStatus", time.Now(), &err)
+	return r.next.SetStatus(ctx, id, status, description)
+}
+
+func (r orderRepository) CountByStatus(ctx context.Context) (_ map[int]int64, err error) {
+	defer r.observe("CountByStatus", time.Now(), &err)
+	return r.next.CountByStatus(ctx)
+}
+
+type outboxRepository struct {
+	next port.OutboxRepository
+	timer
+}
+
+func (m *Metrics) InstrumentOutbox(repo port.OutboxRepository) port.OutboxRepository {
+	return outboxRepository{next: repo, timer: timer{m: m, repository: "outbox"}}
+}
+
+func (r outboxRepository) Save(ctx context.Context, events ...*domain.Event) (err error) {
+	defer r.observe("Save", time.Now(), &err)
+	return r.next.Save(ctx, events...)
+}
+
+func (r outboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) (_ []domain.OutboxMessage, err error) {
+	defer r.observe("ClaimPending", time.Now(), &err)
+	return r.next.ClaimPending(ctx, limit, lease)
+}
+
+func (r outboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID) (err error) {
+	defer r.observe("MarkDelivered", time.Now(), &err)
+	return r.next.MarkDelivered(ctx, id)
+}
+
+func (r outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) (err error) {
+	defer r.observe("MarkFailed", time.Now(), &err)
+	return r.next.MarkFailed(ctx, id, reason, nextAttemptAt)
+}
+
+type webhookSubscriptionRepository struct {
+	next port.WebhookSubscriptionRepository
+	timer
+}
+
+func (m *Metrics) InstrumentWebhookSubscriptions(repo port.WebhookSubscriptionRepository) port.WebhookSubscriptionRepository {
+	return webhookSubscriptionRepository{next: repo, timer: timer{m: m, repository: "webhook_subscriptions"}}
+}
+
+func (r webhookSubscriptionRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (_ *domain.WebhookSubscription, err error) {
+	defer r.observe("CreateSubscription", time.Now(), &err)
+	return r.next.CreateSubscription(ctx, subscription)
+}
+
+func (r webhookSubscriptionRepository) GetSubscriptionByID(ctx context.Context, id string) (_ *domain.WebhookSubscription, err error) {
+	defer r.observe("GetSubscriptionByID", time.Now(), &err)
+	return r.next.GetSubscriptionByID(ctx, id)
+}
+
+func (r webhookSubscriptionRepository) GetSubscriptions(ctx context.Context, page, limit int) (_ []domain.WebhookSubscription, _ int64, err error) {
+	defer r.observe("GetSubscriptions", time.Now(), &err)
+	return r.next.GetSubscriptions(ctx, page, limit)
+}
+
+func (r webhookSubscriptionRepository) GetSubscriptionsByEventType(ctx context.Context, eventType string) (_ []domain.WebhookSubscription, err error) {
+	defer r.observe("GetSubscriptionsByEventType", time.Now(), &err)
+	return r.next.GetSubscriptionsByEventType(ctx, eventType)
+}
+
+func (r webhookSubscriptionRepository) ReplaceSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (_ *domain.WebhookSubscription, err error) {
+	defer r.observe("ReplaceSubscription", time.Now(), &err)
+	return r.next.ReplaceSubscription(ctx, subscription)
+}
+
+func (r webhookSubscriptionRepository) DeleteSubscription(ctx context.Context, id string) (err error) {
+	defer r.observe("DeleteSubscription", time.Now(), &err)
+	return r.next.DeleteSubscription(ctx, id)
+}
+
+type webhookDeliveryRepository struct {
+	next port.WebhookDeliveryRepository
+	timer
+}
+
+func (m *Metrics) InstrumentWebhookDeliveries(repo port.WebhookDeliveryRepository) port.WebhookDeliveryRepository {
+	return webhookDeliveryRepository{next: repo, timer: timer{m: m, repository: "webhook_deliveries"}}
+}
+
+func (r webhookDeliveryRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (err error) {
+	defer r.observe("CreateDelivery", time.Now(), &err)
+	return r.next.CreateDelivery(ctx, delivery)
+}
+
+func (r webhookDeliveryRepository) GetDeliveryByID(ctx context.Context, id string) (_ *domain.WebhookDelivery, err error) {
+	defer r.observe("GetDeliveryByID", time.Now(), &err)
+	return r.next.GetDeliveryByID(ctx, id)
+}
+
+func (r webhookDeliveryRepository) GetDeliveries(ctx context.Context, subscriptionID string, page, limit int) (_ []domain.WebhookDelivery, _ int64, err error) {
+	defer r.observe("GetDeliveries", time.Now(), &err)
+	return r.next.GetDeliveries(ctx, subscriptionID, page, limit)
+}
+
+func (r webhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) (_ []domain.WebhookDelivery, err error) {
+	defer r.observe("ClaimDue", time.Now(), &err)
+	return r.next.ClaimDue(ctx, limit, lease)
+}
+
+func (r webhookDeliveryRepository) ReplaceDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (err error) {
+	defer r.observe("ReplaceDelivery", time.Now(), &err)
+	return r.next.ReplaceDelivery(ctx, delivery)
+}
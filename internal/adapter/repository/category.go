@@ -2,89 +2,89 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const categoryConflictMessage = "a category with this name already exists"
+
 type CategoryRepository struct {
 	Collection *mongo.Collection
+	timeout    time.Duration
 }
 
-func NewCategoryRepository(db *mongo.Database) *CategoryRepository {
+func NewCategoryRepository(db *mongo.Database, timeout time.Duration) *CategoryRepository {
 	return &CategoryRepository{
 		Collection: db.Collection("categories"),
+		timeout:    timeout,
 	}
 }
 
+func (cr *CategoryRepository) EnsureIndexes(ctx context.Context) error {
+	return createIndexes(ctx, cr.Collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetName("name_unique").SetUnique(true)},
+	})
+}
+
 func (cr *CategoryRepository) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
 	_, err := cr.Collection.InsertOne(ctx, category)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, categoryConflictMessage)
 	}
 	return category, nil
 }
 
 func (cr *CategoryRepository) GetCategoryByID(ctx context.Context, id string) (*domain.Category, error) {
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
 	var category domain.Category
 
-	uuid, err := uuid.Parse(id)
+	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, err
 	}
 
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
-
-	filter := bson.M{"_id": binaryUUID}
+	filter := bson.M{"_id": uuidID}
 	err = cr.Collection.FindOne(ctx, filter).Decode(&category)
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, categoryConflictMessage)
 	}
 	return &category, nil
 }
 
 func (cr *CategoryRepository) ReplaceCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
-	uuid, err := uuid.Parse(category.ID.String())
-	if err != nil {
-		return nil, err
-	}
-
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
 
-	filter := bson.M{"_id": binaryUUID}
+	filter := bson.M{"_id": category.ID}
 	update := bson.M{"$set": category}
 
-	_, err = cr.Collection.UpdateOne(ctx, filter, update)
+	result, err := cr.Collection.UpdateOne(ctx, filter, update)
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, categoryConflictMessage)
+	}
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrNotFound
 	}
 	return category, nil
 }
 
 func (cr *CategoryRepository) UpdateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
-	uuid, err := uuid.Parse(category.ID.String())
-	if err != nil {
-		return nil, err
-	}
-
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
 
-	filter := bson.M{"_id": binaryUUID}
+	filter := bson.M{"_id": category.ID}
 	update := bson.M{"$set": bson.M{}}
 
 	if category.Name != "" {
@@ -94,58 +94,70 @@ func (cr *CategoryRepository) UpdateCategory(ctx context.Context, category *doma
 		update["$set"].(bson.M)["description"] = category.Description
 	}
 
-	_, err = cr.Collection.UpdateOne(ctx, filter, update)
+	result, err := cr.Collection.UpdateOne(ctx, filter, update)
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, categoryConflictMessage)
+	}
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrNotFound
 	}
 	return category, nil
 }
 
 func (cr *CategoryRepository) DeleteCategory(ctx context.Context, id string) error {
-	uuid, err := uuid.Parse(id)
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
 
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
-
-	filter := bson.M{"_id": binaryUUID}
-	_, err = cr.Collection.DeleteOne(ctx, filter)
+	filter := bson.M{"_id": uuidID}
+	result, err := cr.Collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
 	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
 	return nil
 }
 
-func (cr *CategoryRepository) GetCategories(ctx context.Context, page, limit int) ([]domain.Category, error) {
+func (cr *CategoryRepository) GetCategories(ctx context.Context, page, limit int) ([]domain.Category, int64, error) {
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
 	if page == 0 {
 		page = 1
 	}
 	if limit == 0 {
 		limit = 10
 	}
+	total, err := cr.Collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+
 	var categories []domain.Category
 	opts := options.Find().SetSkip(int64((page - 1) * limit)).SetLimit(int64(limit))
 	cursor, err := cr.Collection.Find(ctx, bson.M{}, opts)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer cursor.Close(ctx)
 
 	for cursor.Next(ctx) {
 		var category domain.Category
 		if err = cursor.Decode(&category); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		categories = append(categories, category)
 	}
 	if err := cursor.Err(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return categories, nil
+	return categories, total, nil
 }
@@ -2,35 +2,53 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const clientConflictMessage = "a client with this CPF already exists"
+
 type ClientRepository struct {
 	Collection *mongo.Collection
+	timeout    time.Duration
 }
 
-func NewClientRepository(db *mongo.Database) *ClientRepository {
+func NewClientRepository(db *mongo.Database, timeout time.Duration) *ClientRepository {
 	return &ClientRepository{
 		Collection: db.Collection("clients"),
+		timeout:    timeout,
 	}
 }
 
+func (r *ClientRepository) EnsureIndexes(ctx context.Context) error {
+	return createIndexes(ctx, r.Collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "cpf", Value: 1}}, Options: options.Index().SetName("cpf_unique").SetUnique(true)},
+	})
+}
+
 func (r *ClientRepository) CreateClient(ctx context.Context, client *domain.Client) (*domain.Client, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	_, err := r.Collection.InsertOne(ctx, client)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, clientConflictMessage)
 	}
 	return client, nil
 }
 
-func (r *ClientRepository) GetClientByCPF(ctx context.Context, cpf string) (*domain.Client, error) {
+func (r *ClientRepository) GetClientByCPF(ctx context.Context, cpf domain.CPF) (*domain.Client, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	var client domain.Client
 	err := r.Collection.FindOne(ctx, bson.M{"cpf": cpf}).Decode(&client)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, clientConflictMessage)
 	}
 	return &client, nil
 }
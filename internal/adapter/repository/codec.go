@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// NewRegistry returns the BSON registry the Mongo client must be configured with.
+// It encodes uuid.UUID as binary subtype 0x04, so documents and filters built from
+// domain values always agree. Subtype 0x00 is still accepted when decoding legacy documents.
+func NewRegistry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+	registry.RegisterTypeEncoder(uuidType, bsoncodec.ValueEncoderFunc(encodeUUID))
+	registry.RegisterTypeDecoder(uuidType, bsoncodec.ValueDecoderFunc(decodeUUID))
+	return registry
+}
+
+func encodeUUID(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != uuidType {
+		return bsoncodec.ValueEncoderError{Name: "UUIDEncodeValue", Types: []reflect.Type{uuidType}, Received: val}
+	}
+	id := val.Interface().(uuid.UUID)
+	return vw.WriteBinaryWithSubtype(id[:], bsontype.BinaryUUID)
+}
+
+func decodeUUID(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != uuidType {
+		return bsoncodec.ValueDecoderError{Name: "UUIDDecodeValue", Types: []reflect.Type{uuidType}, Received: val}
+	}
+
+	switch vr.Type() {
+	case bsontype.Null:
+		val.Set(reflect.ValueOf(uuid.Nil))
+		return vr.ReadNull()
+	case bsontype.Binary:
+		data, subtype, err := vr.ReadBinary()
+		if err != nil {
+			return err
+		}
+		if subtype != bsontype.BinaryUUID && subtype != bsontype.BinaryGeneric {
+			return fmt.Errorf("cannot decode binary subtype %#x into a UUID", subtype)
+		}
+		id, err := uuid.FromBytes(data)
+		if err != nil {
+			return err
+		}
+		val.Set(reflect.ValueOf(id))
+		return nil
+	default:
+		return fmt.Errorf("cannot decode %v into a UUID", vr.Type())
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/description"
+
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+)
+
+// withTimeout bounds a repository call by the timeout its repository was built with, unless
+// ctx has an earlier deadline, so a stalled server fails the request instead of holding it
+// and its pooled connection.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, timeout)
+}
+
+// ConnectionMonitor follows the topology events of the driver to know whether a writable
+// server is reachable. The driver reconnects on its own; the monitor logs when the connection
+// is lost and regained and reports it to the readiness check without waiting for a timeout.
+type ConnectionMonitor struct {
+	mu        sync.RWMutex
+	connected bool
+	since     time.Time
+	lastError error
+}
+
+func NewConnectionMonitor() *ConnectionMonitor {
+	return &ConnectionMonitor{since: time.Now()}
+}
+
+// ServerMonitor returns the driver monitor to set with options.Client().SetServerMonitor.
+func (m *ConnectionMonitor) ServerMonitor() *event.ServerMonitor {
+	return &event.ServerMonitor{
+		TopologyDescriptionChanged: func(e *event.TopologyDescriptionChangedEvent) {
+			m.update(e.NewDescription)
+		},
+	}
+}
+
+func (m *ConnectionMonitor) update(topology description.Topology) {
+	connected := topology.HasWritableServer()
+
+	var errs []error
+	for _, server := range topology.Servers {
+		if server.LastError != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", server.Addr, server.LastError))
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastError = errors.Join(errs...)
+	if connected == m.connected {
+		return
+	}
+
+	logger := logging.FromContext(context.Background())
+	if connected {
+		logger.Info("connected to MongoDB", "disconnected_for", time.Since(m.since).String())
+	} else {
+		logger.Error("lost connection to MongoDB, the driver keeps reconnecting", "error", m.lastError)
+	}
+	m.connected = connected
+	m.since = time.Now()
+}
+
+// Check returns an error while no writable server is reachable.
+func (m *ConnectionMonitor) Check(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.connected {
+		return nil
+	}
+	if m.lastError != nil {
+		return fmt.Errorf("no writable MongoDB server since %s: %w", m.since.Format(time.RFC3339), m.lastError)
+	}
+	return fmt.Errorf("no writable MongoDB server since %s", m.since.Format(time.RFC3339))
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo/address"
+	"go.mongodb.org/mongo-driver/mongo/description"
+)
+
+func TestConnectionMonitor(t *testing.T) {
+	ctx := context.Background()
+	monitor := NewConnectionMonitor()
+
+	if err := monitor.Check(ctx); err == nil {
+		t.Fatal("Check succeeded before any topology event")
+	}
+
+	monitor.update(description.Topology{
+		Kind:    description.Single,
+		Servers: []description.Server{{Addr: address.Address("mongodb:27017"), Kind: description.Standalone}},
+	})
+	if err := monitor.Check(ctx); err != nil {
+		t.Fatalf("Check with a standalone server: %v", err)
+	}
+
+	monitor.update(description.Topology{
+		Kind: description.Single,
+		Servers: []description.Server{{
+			Addr:      address.Address("mongodb:27017"),
+			Kind:      description.Unknown,
+			LastError: errors.New("connection refused"),
+		}},
+	})
+	err := monitor.Check(ctx)
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("Check after losing the server = %v, want the server error", err)
+	}
+
+	monitor.update(description.Topology{
+		Kind:    description.ReplicaSetWithPrimary,
+		Servers: []description.Server{{Addr: address.Address("mongodb:27017"), Kind: description.RSPrimary}},
+	})
+	if err := monitor.Check(ctx); err != nil {
+		t.Fatalf("Check after reconnecting: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Indexer is implemented by repositories that declare the indexes of their collection.
+type Indexer interface {
+	EnsureIndexes(ctx context.Context) error
+}
+
+// EnsureIndexes creates the indexes declared by each repository. Creating an index
+// that already exists with the same definition is a no-op, so it is safe to run on every startup.
+func EnsureIndexes(ctx context.Context, indexers ...Indexer) error {
+	for _, indexer := range indexers {
+		if err := indexer.EnsureIndexes(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createIndexes(ctx context.Context, collection *mongo.Collection, models []mongo.IndexModel) error {
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("failed to create indexes on %s: %w", collection.Name(), err)
+	}
+	return nil
+}
+
+// translateError maps driver errors to the domain errors the services and handlers understand.
+func translateError(err error, conflictMessage string) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %s", domain.ErrConflict, conflictMessage)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTranslateError(t *testing.T) {
+	duplicateWrite := mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error collection: skinaapis.categories index: name_unique"}}}
+	duplicateCommand := mongo.CommandError{Code: 11000, Message: "E11000 duplicate key error"}
+	otherWrite := mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 121, Message: "Document failed validation"}}}
+
+	for _, err := range []error{duplicateWrite, duplicateCommand} {
+		translated := translateError(err, "a category with this name already exists")
+		if !errors.Is(translated, domain.ErrConflict) {
+			t.Errorf("translateError(%v) = %v, want ErrConflict", err, translated)
+		}
+		if !strings.Contains(translated.Error(), "a category with this name already exists") {
+			t.Errorf("translateError(%v) = %q, want the conflict message", err, translated)
+		}
+	}
+
+	if translated := translateError(otherWrite, "conflict"); errors.Is(translated, domain.ErrConflict) {
+		t.Errorf("translateError(%v) = %v, want the error unchanged", otherWrite, translated)
+	}
+	if translated := translateError(nil, "conflict"); translated != nil {
+		t.Errorf("translateError(nil) = %v, want nil", translated)
+	}
+}
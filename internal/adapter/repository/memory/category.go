@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+const categoryConflictMessage = "a category with this name already exists"
+
+type CategoryRepository struct {
+	*table[uuid.UUID, domain.Category]
+}
+
+func NewCategoryRepository() *CategoryRepository {
+	return &CategoryRepository{table: newTable[uuid.UUID, domain.Category]()}
+}
+
+func (r *CategoryRepository) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	created := r.insert(ctx, category.ID, *category, func(other domain.Category) bool {
+		return other.Name == category.Name
+	})
+	if !created {
+		return nil, fmt.Errorf("%w: %s", domain.ErrConflict, categoryConflictMessage)
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) GetCategoryByID(ctx context.Context, id string) (*domain.Category, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	category, ok := r.get(uuidID)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepository) GetCategories(ctx context.Context, page, limit int) ([]domain.Category, int64, error) {
+	categories, total := paginate(r.all(nil), page, limit)
+	return categories, total, nil
+}
+
+func (r *CategoryRepository) ReplaceCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	if err := r.checkNameConflict(category); err != nil {
+		return nil, err
+	}
+
+	if !r.update(ctx, category.ID, func(row *domain.Category) bool {
+		*row = *category
+		return true
+	}) {
+		return nil, domain.ErrNotFound
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	if err := r.checkNameConflict(category); err != nil {
+		return nil, err
+	}
+
+	if !r.update(ctx, category.ID, func(row *domain.Category) bool {
+		if category.Name != "" {
+			row.Name = category.Name
+		}
+		if category.Description != "" {
+			row.Description = category.Description
+		}
+		return true
+	}) {
+		return nil, domain.ErrNotFound
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, id string) error {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	if !r.delete(ctx, uuidID) {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *CategoryRepository) checkNameConflict(category *domain.Category) error {
+	if category.Name == "" {
+		return nil
+	}
+
+	conflicts := r.all(func(other domain.Category) bool {
+		return other.ID != category.ID && other.Name == category.Name
+	})
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrConflict, categoryConflictMessage)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+type ClientRepository struct {
+	*table[domain.CPF, domain.Client]
+}
+
+func NewClientRepository() *ClientRepository {
+	return &ClientRepository{table: newTable[domain.CPF, domain.Client]()}
+}
+
+func (r *ClientRepository) CreateClient(ctx context.Context, client *domain.Client) (*domain.Client, error) {
+	if !r.insert(ctx, client.Cpf, *client, nil) {
+		return nil, fmt.Errorf("%w: a client with this CPF already exists", domain.ErrConflict)
+	}
+	return client, nil
+}
+
+func (r *ClientRepository) GetClientByCPF(ctx context.Context, cpf domain.CPF) (*domain.Client, error) {
+	client, ok := r.get(cpf)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &client, nil
+}
@@ -0,0 +1,122 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/memory"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/repositorytest"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+func TestContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		return repositorytest.Repositories{
+			Categories: memory.NewCategoryRepository(),
+			Products:   memory.NewProductRepository(),
+			Clients:    memory.NewClientRepository(),
+			Orders:     memory.NewOrderRepository(),
+		}
+	})
+}
+
+func TestUnitOfWorkRestoresOnError(t *testing.T) {
+	ctx := context.Background()
+	categories := memory.NewCategoryRepository()
+	uow := memory.NewUnitOfWork()
+
+	category, err := domain.NewCategory("Lanche", "Hamburgers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("boom")
+	err = uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := categories.CreateCategory(ctx, category); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Do = %v, want %v", err, failure)
+	}
+
+	if _, err := categories.GetCategoryByID(ctx, category.ID.String()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("category written by the failed unit is still visible: %v", err)
+	}
+}
+
+func TestUnitOfWorkRollbackKeepsWritesMadeOutsideIt(t *testing.T) {
+	ctx := context.Background()
+	categories := memory.NewCategoryRepository()
+	uow := memory.NewUnitOfWork()
+
+	renamed, err := domain.NewCategory("Lanche", "Hamburgers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := domain.NewCategory("Bebida", "Drinks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, category := range []*domain.Category{renamed, deleted} {
+		if _, err := categories.CreateCategory(ctx, category); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outside, err := domain.NewCategory("Sobremesa", "Desserts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := make(chan error)
+
+	failure := errors.New("boom")
+	err = uow.Do(ctx, func(ctx context.Context) error {
+		// Another request writes while the unit runs, e.g. from its own goroutine.
+		go func() {
+			_, err := categories.CreateCategory(context.Background(), outside)
+			written <- err
+		}()
+		if err := <-written; err != nil {
+			return err
+		}
+
+		update := *renamed
+		update.Name = "Lanches"
+		if _, err := categories.ReplaceCategory(ctx, &update); err != nil {
+			return err
+		}
+		if err := categories.DeleteCategory(ctx, deleted.ID.String()); err != nil {
+			return err
+		}
+		// Nested units join the outer one and are rolled back with it.
+		return uow.Do(ctx, func(ctx context.Context) error {
+			inner, err := domain.NewCategory("Porção", "Sides")
+			if err != nil {
+				return err
+			}
+			if _, err := categories.CreateCategory(ctx, inner); err != nil {
+				return err
+			}
+			return failure
+		})
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Do = %v, want %v", err, failure)
+	}
+
+	got, _, err := categories.GetCategories(ctx, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, category := range got {
+		names = append(names, category.Name)
+	}
+	if want := []string{"Lanche", "Bebida", "Sobremesa"}; !slices.Equal(names, want) {
+		t.Fatalf("categories after the rollback = %v, want %v", names, want)
+	}
+}
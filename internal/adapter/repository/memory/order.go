@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+type OrderRepository struct {
+	*table[uuid.UUID, domain.Order]
+}
+
+func NewOrderRepository() *OrderRepository {
+	return &OrderRepository{table: newTable[uuid.UUID, domain.Order]()}
+}
+
+func (r *OrderRepository) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	r.put(ctx, order.ID, cloneOrder(*order))
+	return order, nil
+}
+
+func (r *OrderRepository) GetOrderByID(ctx context.Context, id string) (*domain.Order, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	order, ok := r.get(uuidID)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	order = cloneOrder(order)
+	return &order, nil
+}
+
+func (r *OrderRepository) GetOrders(ctx context.Context, filter domain.OrderFilter, order domain.OrderSort, page, pageSize int) ([]domain.Order, int64, error) {
+	orders := r.all(filter.Matches)
+	sort.Slice(orders, func(i, j int) bool {
+		return order.Less(orders[i], orders[j])
+	})
+
+	orders, total := paginate(orders, page, pageSize)
+	for i := range orders {
+		orders[i] = cloneOrder(orders[i])
+	}
+	return orders, total, nil
+}
+
+func (r *OrderRepository) GetOrdersByCursor(ctx context.Context, query domain.OrderCursorQuery) ([]domain.Order, error) {
+	orders := r.all(func(order domain.Order) bool {
+		if !query.Filter.Matches(order) {
+			return false
+		}
+		position := domain.NewOrderCursor(order)
+		if query.After != nil && !query.After.Precedes(position) {
+			return false
+		}
+		return query.Before == nil || position.Precedes(*query.Before)
+	})
+	sort.Slice(orders, func(i, j int) bool {
+		return domain.NewOrderCursor(orders[i]).Precedes(domain.NewOrderCursor(orders[j]))
+	})
+
+	// Orders before the cursor are the ones right before it, the end of the newer orders.
+	if query.Before != nil && len(orders) > query.Limit {
+		orders = orders[len(orders)-query.Limit:]
+	}
+	if len(orders) > query.Limit {
+		orders = orders[:query.Limit]
+	}
+	for i := range orders {
+		orders[i] = cloneOrder(orders[i])
+	}
+	return orders, nil
+}
+
+func (r *OrderRepository) SetStatus(ctx context.Context, id uuid.UUID, status int, description string) error {
+	if !r.update(ctx, id, func(row *domain.Order) bool {
+		row.Status = status
+		row.StatusDescription = description
+		row.UpdatedAt = time.Now()
+		return true
+	}) {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OrderRepository) CountByStatus(ctx context.Context) (map[int]int64, error) {
+	counts := map[int]int64{}
+	for _, order := range r.all(nil) {
+		counts[order.Status]++
+	}
+	return counts, nil
+}
+
+// cloneOrder copies the items so callers never share them with the stored row.
+func cloneOrder(order domain.Order) domain.Order {
+	order.Items = append([]domain.OrderItem(nil), order.Items...)
+	return order
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+type OutboxRepository struct {
+	*table[uuid.UUID, domain.OutboxMessage]
+}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{table: newTable[uuid.UUID, domain.OutboxMessage]()}
+}
+
+func (r *OutboxRepository) Save(ctx context.Context, events ...*domain.Event) error {
+	for _, event := range events {
+		r.put(ctx, event.ID, domain.OutboxMessage{Event: *event, NextAttemptAt: event.OccurredAt})
+	}
+	return nil
+}
+
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var messages []domain.OutboxMessage
+	for _, key := range r.order {
+		message := r.rows[key]
+		if message.DeliveredAt == nil && !message.NextAttemptAt.After(now) {
+			messages = append(messages, message)
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].OccurredAt.Before(messages[j].OccurredAt)
+	})
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+
+	for _, message := range messages {
+		r.remember(ctx, message.ID)
+		claimed := r.rows[message.ID]
+		claimed.NextAttemptAt = now.Add(lease)
+		r.rows[message.ID] = claimed
+	}
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	r.update(ctx, id, func(row *domain.OutboxMessage) bool {
+		now := time.Now()
+		row.DeliveredAt = &now
+		row.LastError = ""
+		row.Attempts++
+		return true
+	})
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	r.update(ctx, id, func(row *domain.OutboxMessage) bool {
+		row.NextAttemptAt = nextAttemptAt
+		row.LastError = reason
+		row.Attempts++
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+type ProductRepository struct {
+	*table[uuid.UUID, domain.Product]
+	// sales holds the units ordered of each product, for the popularity sort.
+	sales *table[uuid.UUID, int64]
+}
+
+func NewProductRepository() *ProductRepository {
+	return &ProductRepository{
+		table: newTable[uuid.UUID, domain.Product](),
+		sales: newTable[uuid.UUID, int64](),
+	}
+}
+
+func (r *ProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	r.put(ctx, product.ID, *product)
+	return product, nil
+}
+
+func (r *ProductRepository) GetProductByID(ctx context.Context, id string) (*domain.Product, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	product, ok := r.get(uuidID)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &product, nil
+}
+
+func (r *ProductRepository) GetProducts(ctx context.Context, filter domain.ProductFilter, order domain.ProductSort, page, limit int) ([]domain.Product, int64, error) {
+	products := r.all(filter.Matches)
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+
+		var c int
+		switch order.Field {
+		case domain.ProductSortName:
+			c = cmp.Compare(a.Name, b.Name)
+		case domain.ProductSortPrice:
+			c = cmp.Compare(a.Price, b.Price)
+		case domain.ProductSortPopularity:
+			soldA, _ := r.sales.get(a.ID)
+			soldB, _ := r.sales.get(b.ID)
+			c = cmp.Compare(soldA, soldB)
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if c == 0 {
+			c = bytes.Compare(a.ID[:], b.ID[:])
+		}
+		if order.Descending {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	products, total := paginate(products, page, limit)
+	return products, total, nil
+}
+
+func (r *ProductRepository) ReplaceProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	if !r.update(ctx, product.ID, func(row *domain.Product) bool {
+		*row = *product
+		return true
+	}) {
+		return nil, domain.ErrNotFound
+	}
+	return product, nil
+}
+
+func (r *ProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	if !r.update(ctx, product.ID, func(row *domain.Product) bool {
+		if product.CategoryId != uuid.Nil {
+			row.CategoryId = product.CategoryId
+		}
+		if product.Name != "" {
+			row.Name = product.Name
+		}
+		if product.Description != "" {
+			row.Description = product.Description
+		}
+		if product.Price != 0 {
+			row.Price = product.Price
+		}
+		if product.Image != "" {
+			row.Image = product.Image
+		}
+		if !product.UpdatedAt.IsZero() {
+			row.UpdatedAt = product.UpdatedAt
+		}
+		return true
+	}) {
+		return nil, domain.ErrNotFound
+	}
+	return product, nil
+}
+
+func (r *ProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	if !r.delete(ctx, uuidID) {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProductRepository) SetAvailability(ctx context.Context, id uuid.UUID, availability domain.Availability) error {
+	if !r.update(ctx, id, func(row *domain.Product) bool {
+		row.Availability = availability
+		row.UpdatedAt = time.Now()
+		return true
+	}) {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProductRepository) RecordSales(ctx context.Context, items []domain.OrderItem) error {
+	for _, item := range items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return err
+		}
+		if _, ok := r.get(productID); !ok {
+			continue
+		}
+		if !r.sales.update(ctx, productID, func(sold *int64) bool {
+			*sold += item.Quantity
+			return true
+		}) {
+			r.sales.put(ctx, productID, item.Quantity)
+		}
+	}
+	return nil
+}
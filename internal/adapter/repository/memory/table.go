@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// table keeps rows in insertion order, which stands in for MongoDB's natural order.
+type table[K comparable, V any] struct {
+	mu    sync.RWMutex
+	rows  map[K]V
+	order []K
+}
+
+func newTable[K comparable, V any]() *table[K, V] {
+	return &table[K, V]{rows: make(map[K]V)}
+}
+
+func (t *table[K, V]) get(key K) (V, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	row, ok := t.rows[key]
+	return row, ok
+}
+
+// The writes below take the context of the caller so that, inside a unit of work, they
+// are recorded in the unit's journal and undone if the unit fails.
+
+// put inserts or replaces the row stored under key.
+func (t *table[K, V]) put(ctx context.Context, key K, row V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.remember(ctx, key)
+	if _, ok := t.rows[key]; !ok {
+		t.order = append(t.order, key)
+	}
+	t.rows[key] = row
+}
+
+// insert stores row under key unless the key exists or exists reports a conflicting row.
+func (t *table[K, V]) insert(ctx context.Context, key K, row V, exists func(V) bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.rows[key]; ok {
+		return false
+	}
+	for _, other := range t.rows {
+		if exists != nil && exists(other) {
+			return false
+		}
+	}
+
+	t.remember(ctx, key)
+	t.order = append(t.order, key)
+	t.rows[key] = row
+	return true
+}
+
+// update applies fn to the row stored under key and reports whether it was found.
+// fn may reject the change by returning false.
+func (t *table[K, V]) update(ctx context.Context, key K, fn func(row *V) bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	row, ok := t.rows[key]
+	if !ok || !fn(&row) {
+		return false
+	}
+	t.remember(ctx, key)
+	t.rows[key] = row
+	return true
+}
+
+func (t *table[K, V]) delete(ctx context.Context, key K) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.rows[key]; !ok {
+		return false
+	}
+	t.remember(ctx, key)
+	t.remove(key)
+	return true
+}
+
+func (t *table[K, V]) remove(key K) {
+	delete(t.rows, key)
+	if i := slices.Index(t.order, key); i >= 0 {
+		t.order = slices.Delete(t.order, i, i+1)
+	}
+}
+
+// remember records in the journal of the unit of work running ctx, if any, how to put
+// back the row stored under key. It must be called with t.mu held, before the row changes.
+// Only the rows the unit writes are put back, so writes made outside the unit survive
+// its rollback unless they changed the same rows.
+func (t *table[K, V]) remember(ctx context.Context, key K) {
+	journal := journalFrom(ctx)
+	if journal == nil {
+		return
+	}
+
+	row, existed := t.rows[key]
+	position := slices.Index(t.order, key)
+	journal.record(func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if !existed {
+			t.remove(key)
+			return
+		}
+		if _, ok := t.rows[key]; !ok {
+			t.order = slices.Insert(t.order, min(position, len(t.order)), key)
+		}
+		t.rows[key] = row
+	})
+}
+
+// all returns the rows accepted by match, in insertion order.
+func (t *table[K, V]) all(match func(V) bool) []V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	rows := []V{}
+	for _, key := range t.order {
+		row := t.rows[key]
+		if match == nil || match(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// paginate returns the rows of the given 1-based page and the number of rows across all pages.
+func paginate[V any](rows []V, page, limit int) ([]V, int64) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	total := int64(len(rows))
+	start := (page - 1) * limit
+	if start >= len(rows) {
+		return []V{}, total
+	}
+	end := start + limit
+	if end > len(rows) {
+		end = len(rows)
+	}
+	return rows[start:end], total
+}
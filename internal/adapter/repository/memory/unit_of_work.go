@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+)
+
+type unitOfWorkKey struct{}
+
+// UnitOfWork implements port.UnitOfWork for tests and single process deployments. Units
+// run one at a time and the repositories record every row a unit writes, so when the unit
+// fails those rows are put back as they were before it started. Writes made outside a
+// unit are not isolated from it, but its rollback leaves them alone.
+type UnitOfWork struct {
+	mu sync.Mutex
+}
+
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{}
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if journalFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	journal := &journal{}
+	if err := fn(context.WithValue(ctx, unitOfWorkKey{}, journal)); err != nil {
+		journal.rollback()
+		logging.FromContext(ctx).Debug("unit of work rolled back", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// journal collects how to undo the writes of a unit of work, in the order they were made.
+type journal struct {
+	mu    sync.Mutex
+	undos []func()
+}
+
+// journalFrom returns the journal of the unit of work running ctx, or nil outside a unit.
+func journalFrom(ctx context.Context) *journal {
+	journal, _ := ctx.Value(unitOfWorkKey{}).(*journal)
+	return journal
+}
+
+func (j *journal) record(undo func()) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.undos = append(j.undos, undo)
+}
+
+// rollback undoes the recorded writes, latest first.
+func (j *journal) rollback() {
+	j.mu.Lock()
+	undos := j.undos
+	j.undos = nil
+	j.mu.Unlock()
+
+	for i := len(undos) - 1; i >= 0; i-- {
+		undos[i]()
+	}
+}
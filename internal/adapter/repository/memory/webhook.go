@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+type WebhookSubscriptionRepository struct {
+	*table[uuid.UUID, domain.WebhookSubscription]
+}
+
+func NewWebhookSubscriptionRepository() *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{table: newTable[uuid.UUID, domain.WebhookSubscription]()}
+}
+
+func (r *WebhookSubscriptionRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	r.put(ctx, subscription.ID, cloneSubscription(*subscription))
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, ok := r.get(uuidID)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	subscription = cloneSubscription(subscription)
+	return &subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptions(ctx context.Context, page, limit int) ([]domain.WebhookSubscription, int64, error) {
+	subscriptions, total := paginate(r.all(nil), page, limit)
+	return cloneSubscriptions(subscriptions), total, nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptionsByEventType(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	return cloneSubscriptions(r.all(func(subscription domain.WebhookSubscription) bool {
+		return subscription.Matches(eventType)
+	})), nil
+}
+
+func (r *WebhookSubscriptionRepository) ReplaceSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if !r.update(ctx, subscription.ID, func(row *domain.WebhookSubscription) bool {
+		*row = cloneSubscription(*subscription)
+		return true
+	}) {
+		return nil, domain.ErrNotFound
+	}
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) DeleteSubscription(ctx context.Context, id string) error {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	if !r.delete(ctx, uuidID) {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func cloneSubscription(subscription domain.WebhookSubscription) domain.WebhookSubscription {
+	subscription.EventTypes = append([]string(nil), subscription.EventTypes...)
+	return subscription
+}
+
+func cloneSubscriptions(subscriptions []domain.WebhookSubscription) []domain.WebhookSubscription {
+	for i := range subscriptions {
+		subscriptions[i] = cloneSubscription(subscriptions[i])
+	}
+	return subscriptions
+}
+
+type WebhookDeliveryRepository struct {
+	*table[uuid.UUID, domain.WebhookDelivery]
+}
+
+func NewWebhookDeliveryRepository() *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{table: newTable[uuid.UUID, domain.WebhookDelivery]()}
+}
+
+func (r *WebhookDeliveryRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.insert(ctx, delivery.ID, cloneDelivery(*delivery), nil)
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) GetDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, ok := r.get(uuidID)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	delivery = cloneDelivery(delivery)
+	return &delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) GetDeliveries(ctx context.Context, subscriptionID string, page, limit int) ([]domain.WebhookDelivery, int64, error) {
+	uuidID, err := uuid.Parse(subscriptionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := r.all(func(delivery domain.WebhookDelivery) bool {
+		return delivery.SubscriptionID == uuidID
+	})
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+
+	deliveries, total := paginate(deliveries, page, limit)
+	for i := range deliveries {
+		deliveries[i] = cloneDelivery(deliveries[i])
+	}
+	return deliveries, total, nil
+}
+
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var deliveries []domain.WebhookDelivery
+	for _, key := range r.order {
+		delivery := r.rows[key]
+		if delivery.Status == domain.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, cloneDelivery(delivery))
+		}
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	for _, delivery := range deliveries {
+		r.remember(ctx, delivery.ID)
+		claimed := r.rows[delivery.ID]
+		claimed.NextAttemptAt = now.Add(lease)
+		r.rows[delivery.ID] = claimed
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) ReplaceDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.update(ctx, delivery.ID, func(row *domain.WebhookDelivery) bool {
+		*row = cloneDelivery(*delivery)
+		return true
+	})
+	return nil
+}
+
+func cloneDelivery(delivery domain.WebhookDelivery) domain.WebhookDelivery {
+	delivery.Attempts = append([]domain.WebhookAttempt{}, delivery.Attempts...)
+	delivery.Payload = append([]byte(nil), delivery.Payload...)
+	return delivery
+}
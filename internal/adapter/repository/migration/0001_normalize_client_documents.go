@@ -0,0 +1,112 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NormalizeClientDocuments rewrites CPFs and e-mails stored before the value objects
+// normalized them, in both the clients collection and the client reference of orders.
+// When a normalized CPF collides with an already normalized client, the duplicate keeps its
+// CPF as stored and is logged with the ID of the client it collides with, to be merged by hand.
+// Values that are not valid CPFs or e-mails are left untouched.
+func NormalizeClientDocuments(ctx context.Context, db *mongo.Database) error {
+	if err := normalizeClients(ctx, db.Collection("clients")); err != nil {
+		return fmt.Errorf("failed to normalize clients: %w", err)
+	}
+
+	if err := normalizeOrderClients(ctx, db.Collection("orders")); err != nil {
+		return fmt.Errorf("failed to normalize order clients: %w", err)
+	}
+
+	return nil
+}
+
+func normalizeClients(ctx context.Context, collection *mongo.Collection) error {
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID   interface{} `bson:"_id"`
+			Cpf  string      `bson:"cpf"`
+			Mail string      `bson:"mail"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		set := bson.M{}
+
+		if cpf, err := domain.NewCPF(doc.Cpf); err == nil && string(cpf) != doc.Cpf {
+			var existing struct {
+				ID interface{} `bson:"_id"`
+			}
+			err := collection.FindOne(ctx, bson.M{"cpf": cpf}, options.FindOne().SetProjection(bson.M{"_id": 1})).Decode(&existing)
+			switch {
+			case err == nil:
+				logging.FromContext(ctx).Warn("client CPF collides with another client once normalized, left unnormalized",
+					"client_id", doc.ID, "duplicate_of", existing.ID, "cpf", cpf)
+			case errors.Is(err, mongo.ErrNoDocuments):
+				set["cpf"] = cpf
+			default:
+				return err
+			}
+		}
+
+		if mail, err := domain.NewEmail(doc.Mail); err == nil && string(mail) != doc.Mail {
+			set["mail"] = mail
+		}
+
+		if len(set) == 0 {
+			continue
+		}
+
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": set}); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func normalizeOrderClients(ctx context.Context, collection *mongo.Collection) error {
+	filter := bson.M{"client": bson.M{"$not": bson.M{"$regex": "^[0-9]{11}$"}}}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID     interface{} `bson:"_id"`
+			Client string      `bson:"client"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		cpf, err := domain.NewCPF(doc.Client)
+		if err != nil {
+			continue
+		}
+
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{"client": cpf}}); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
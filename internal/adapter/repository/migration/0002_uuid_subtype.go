@@ -0,0 +1,138 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ConvertUUIDSubtype rewrites UUIDs stored as generic binary (subtype 0x00) to the
+// standard UUID subtype 0x04 now written by the registered codec. Since _id cannot be
+// updated in place, those documents are removed and reinserted with the new identifier.
+func ConvertUUIDSubtype(ctx context.Context, db *mongo.Database) error {
+	uow, err := repository.NewUnitOfWork(ctx, db.Client())
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{"categories", "products", "orders"} {
+		if err := convertIDs(ctx, uow, db.Collection(name)); err != nil {
+			return fmt.Errorf("failed to convert %s identifiers: %w", name, err)
+		}
+	}
+
+	if err := convertField(ctx, db.Collection("products"), "category_id"); err != nil {
+		return fmt.Errorf("failed to convert products category_id: %w", err)
+	}
+
+	return nil
+}
+
+func legacyUUIDFilter(field string) bson.M {
+	return bson.M{field: bson.M{"$type": "binData"}}
+}
+
+func standardUUID(value interface{}) (primitive.Binary, bool) {
+	binary, ok := value.(primitive.Binary)
+	if !ok || binary.Subtype != bsontype.BinaryGeneric || len(binary.Data) != 16 {
+		return primitive.Binary{}, false
+	}
+	return primitive.Binary{Subtype: bsontype.BinaryUUID, Data: binary.Data}, true
+}
+
+func convertIDs(ctx context.Context, uow *repository.UnitOfWork, collection *mongo.Collection) error {
+	cursor, err := collection.Find(ctx, legacyUUIDFilter("_id"))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc bson.D
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		original := append(bson.D(nil), doc...)
+		var oldID, newID interface{}
+		converted := false
+		for i := range doc {
+			if doc[i].Key != "_id" {
+				continue
+			}
+			oldID = doc[i].Value
+			doc[i].Value, converted = standardUUID(oldID)
+			newID = doc[i].Value
+		}
+		if !converted {
+			continue
+		}
+
+		if err := moveDocument(ctx, uow, collection, oldID, newID, original, doc); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// moveDocument replaces original, stored under oldID, with doc, stored under newID.
+// Unique indexes on other fields, like the category name, keep both copies from
+// existing at once, so the original is deleted first, in a transaction when the server
+// supports them and otherwise restored if the insert fails.
+func moveDocument(ctx context.Context, uow *repository.UnitOfWork, collection *mongo.Collection, oldID, newID interface{}, original, doc bson.D) error {
+	// Only an earlier run interrupted after the insert leaves the new document behind.
+	err := collection.FindOne(ctx, bson.M{"_id": newID}).Err()
+	if err == nil {
+		_, err = collection.DeleteOne(ctx, bson.M{"_id": oldID})
+		return err
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	return uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := collection.DeleteOne(ctx, bson.M{"_id": oldID}); err != nil {
+			return err
+		}
+		_, err := collection.InsertOne(ctx, doc)
+		if err != nil && !uow.Transactional() {
+			if _, restoreErr := collection.InsertOne(ctx, original); restoreErr != nil {
+				return fmt.Errorf("%w; restoring the original document also failed: %v", err, restoreErr)
+			}
+		}
+		return err
+	})
+}
+
+func convertField(ctx context.Context, collection *mongo.Collection, field string) error {
+	cursor, err := collection.Find(ctx, legacyUUIDFilter(field))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		value, ok := standardUUID(doc[field])
+		if !ok {
+			continue
+		}
+
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, bson.M{"$set": bson.M{field: value}}); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,51 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CountProductSales sets the number of units ordered of every product, which the
+// product list sorts by popularity, from the orders placed before it was counted.
+// Items referencing IDs that are not UUIDs or products that were deleted are skipped.
+func CountProductSales(ctx context.Context, db *mongo.Database) error {
+	products := db.Collection("products")
+
+	cursor, err := db.Collection("orders").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$unwind", Value: "$items"}},
+		{{Key: "$group", Value: bson.M{"_id": "$items.product_id", "sold": bson.M{"$sum": "$items.quantity"}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count order items: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	// Setting instead of incrementing keeps the migration safe to rerun after a failure.
+	if _, err := products.UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"sold": 0}}); err != nil {
+		return err
+	}
+
+	for cursor.Next(ctx) {
+		var sales struct {
+			ProductID string `bson:"_id"`
+			Sold      int64  `bson:"sold"`
+		}
+		if err := cursor.Decode(&sales); err != nil {
+			return err
+		}
+
+		productID, err := uuid.Parse(sales.ProductID)
+		if err != nil {
+			continue
+		}
+		if _, err := products.UpdateOne(ctx, bson.M{"_id": productID}, bson.M{"$set": bson.M{"sold": sales.Sold}}); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,18 @@
+package migration
+
+import (
+	"context"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SetProductAvailability marks the products saved before they had an availability as
+// available, which is how they are treated anyway, so they show a status in responses.
+func SetProductAvailability(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("products").UpdateMany(ctx,
+		bson.M{"availability": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"availability": domain.Availability{Status: domain.AvailabilityAvailable}}})
+	return err
+}
@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"context"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StoreProductSearchText stores the folded name and description of the products saved
+// before the search matched them.
+func StoreProductSearchText(ctx context.Context, db *mongo.Database) error {
+	products := db.Collection("products")
+
+	cursor, err := products.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var product domain.Product
+		if err := cursor.Decode(&product); err != nil {
+			return err
+		}
+		if _, err := products.UpdateOne(ctx, bson.M{"_id": product.ID}, bson.M{"$set": bson.M{"search_text": product.SearchText()}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,217 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationsCollection = "schema_migrations"
+	lockCollection       = "schema_migrations_lock"
+	lockID               = "migrations"
+)
+
+// Migration rewrites existing documents so they match what the current code expects.
+// Versions are applied in ascending order and each one is applied only once.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// Record is the document stored in schema_migrations for every applied migration.
+type Record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+	DurationMs  int64     `bson:"duration_ms"`
+}
+
+// ErrLocked is returned when another instance holds the migration lock for longer than the wait timeout.
+var ErrLocked = errors.New("migrations are locked by another instance")
+
+// All returns the migrations defined in this package, ordered by version.
+func All() []Migration {
+	return []Migration{
+		{Version: 1, Description: "normalize client CPFs and e-mails", Up: NormalizeClientDocuments},
+		{Version: 2, Description: "convert UUIDs to binary subtype 0x04", Up: ConvertUUIDSubtype},
+		{Version: 3, Description: "count product sales", Up: CountProductSales},
+		{Version: 4, Description: "set product availability", Up: SetProductAvailability},
+		{Version: 5, Description: "store product search text", Up: StoreProductSearchText},
+	}
+}
+
+type Runner struct {
+	db          *mongo.Database
+	migrations  []Migration
+	owner       string
+	lockTTL     time.Duration
+	lockTimeout time.Duration
+}
+
+func NewRunner(db *mongo.Database, migrations []Migration) *Runner {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	hostname, _ := os.Hostname()
+
+	return &Runner{
+		db:          db,
+		migrations:  sorted,
+		owner:       fmt.Sprintf("%s-%s", hostname, uuid.NewString()),
+		lockTTL:     5 * time.Minute,
+		lockTimeout: 2 * time.Minute,
+	}
+}
+
+// Pending returns the migrations that were not applied yet, in the order they would run.
+func (r *Runner) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, m := range r.migrations {
+		if !applied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Run applies the pending migrations while holding the migration lock and returns the ones applied.
+// With dryRun set, nothing is written and the pending migrations are returned instead.
+func (r *Runner) Run(ctx context.Context, dryRun bool) ([]Migration, error) {
+	if dryRun {
+		return r.Pending(ctx)
+	}
+
+	if err := r.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.unlock(context.WithoutCancel(ctx))
+
+	// Another instance may have applied the migrations while we waited for the lock.
+	pending, err := r.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range pending {
+		start := time.Now()
+		if err := m.Up(ctx, r.db); err != nil {
+			return done, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+
+		record := Record{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now(),
+			DurationMs:  time.Since(start).Milliseconds(),
+		}
+		if _, err := r.db.Collection(migrationsCollection).InsertOne(ctx, record); err != nil {
+			return done, fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+		}
+		done = append(done, m)
+
+		if err := r.refreshLock(ctx); err != nil {
+			return done, err
+		}
+	}
+
+	return done, nil
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int]bool, error) {
+	cursor, err := r.db.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	applied := make(map[int]bool)
+	for cursor.Next(ctx) {
+		var record Record
+		if err := cursor.Decode(&record); err != nil {
+			return nil, err
+		}
+		applied[record.Version] = true
+	}
+	return applied, cursor.Err()
+}
+
+// lock takes the migration lock, waiting for it while it is held by another owner.
+// A lock whose owner died is taken over once it expires.
+func (r *Runner) lock(ctx context.Context) error {
+	deadline := time.Now().Add(r.lockTimeout)
+
+	for {
+		acquired, err := r.tryLock(ctx)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (r *Runner) tryLock(ctx context.Context) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": lockID,
+		"$or": bson.A{
+			bson.M{"owner": r.owner},
+			bson.M{"expires_at": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": r.owner, "locked_at": now, "expires_at": now.Add(r.lockTTL)}}
+
+	err := r.db.Collection(lockCollection).FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetUpsert(true)).Err()
+	if err == nil || errors.Is(err, mongo.ErrNoDocuments) {
+		return true, nil
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to acquire migration lock: %w", err)
+}
+
+func (r *Runner) refreshLock(ctx context.Context) error {
+	acquired, err := r.tryLock(ctx)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return ErrLocked
+	}
+	return nil
+}
+
+// unlock releases the migration lock. Failing to do so only delays the next run until the lock expires.
+func (r *Runner) unlock(ctx context.Context) {
+	if _, err := r.db.Collection(lockCollection).DeleteOne(ctx, bson.M{"_id": lockID, "owner": r.owner}); err != nil {
+		logging.FromContext(ctx).Warn("failed to release the migration lock", "error", err, "expires_in", r.lockTTL.String())
+	}
+}
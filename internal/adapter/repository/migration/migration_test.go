@@ -0,0 +1,224 @@
+package migration_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/migration"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newDatabase returns a throwaway database on the MongoDB at MONGO_TEST_URI, with the
+// indexes the API creates on startup, before migrations run. The test is skipped when
+// the variable is not set.
+// operationTimeout bounds each repository call in the tests.
+const operationTimeout = 5 * time.Second
+
+func newDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(repository.NewRegistry()))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(ctx) })
+
+	db := client.Database("migration_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16])
+	t.Cleanup(func() { db.Drop(ctx) })
+
+	err = repository.EnsureIndexes(ctx,
+		repository.NewCategoryRepository(db, operationTimeout),
+		repository.NewProductRepository(db, operationTimeout),
+		repository.NewClientRepository(db, operationTimeout),
+		repository.NewOrderRepository(db, operationTimeout))
+	if err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+	return db
+}
+
+func legacyUUID() primitive.Binary {
+	id := uuid.New()
+	return primitive.Binary{Subtype: bsontype.BinaryGeneric, Data: id[:]}
+}
+
+func TestRunnerDryRunWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	db := newDatabase(t)
+	before, err := db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ran := false
+	migrations := []migration.Migration{{Version: 1, Description: "first", Up: func(context.Context, *mongo.Database) error {
+		ran = true
+		return nil
+	}}}
+
+	pending, err := migration.NewRunner(db, migrations).Run(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || ran {
+		t.Fatalf("dry run listed %d migrations and ran them: %v, want 1 listed and none run", len(pending), ran)
+	}
+	after, err := db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before) {
+		t.Fatalf("dry run created collections: %v, had %v", after, before)
+	}
+}
+
+func TestRunnerReturnsTheMigrationsAppliedBeforeAFailure(t *testing.T) {
+	ctx := context.Background()
+	db := newDatabase(t)
+
+	up := func(context.Context, *mongo.Database) error { return nil }
+	migrations := []migration.Migration{
+		{Version: 1, Description: "first", Up: up},
+		{Version: 2, Description: "broken", Up: func(context.Context, *mongo.Database) error { return errors.New("boom") }},
+		{Version: 3, Description: "third", Up: up},
+	}
+	runner := migration.NewRunner(db, migrations)
+
+	applied, err := runner.Run(ctx, false)
+	if err == nil {
+		t.Fatal("Run ignored the failing migration")
+	}
+	if len(applied) != 1 || applied[0].Version != 1 {
+		t.Fatalf("Run returned %+v as applied, want version 1", applied)
+	}
+
+	pending, err := runner.Pending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].Version != 2 || pending[1].Version != 3 {
+		t.Fatalf("pending %+v after the failure, want versions 2 and 3", pending)
+	}
+
+	// The lock was released, so another runner does not wait for it to expire.
+	other := migration.NewRunner(db, migrations[:1])
+	if _, err := other.Run(ctx, false); err != nil {
+		t.Fatalf("Run after the failure: %v", err)
+	}
+}
+
+func TestConvertUUIDSubtype(t *testing.T) {
+	ctx := context.Background()
+	db := newDatabase(t)
+
+	// The unique category name index is in place, so a category cannot be copied
+	// under its new identifier while the original exists.
+	categoryID := legacyUUID()
+	categories := []interface{}{
+		bson.D{{Key: "_id", Value: categoryID}, {Key: "name", Value: "Lanche"}},
+		bson.D{{Key: "_id", Value: legacyUUID()}, {Key: "name", Value: "Bebida"}},
+	}
+	if _, err := db.Collection("categories").InsertMany(ctx, categories); err != nil {
+		t.Fatalf("InsertMany categories: %v", err)
+	}
+	product := bson.D{{Key: "_id", Value: legacyUUID()}, {Key: "name", Value: "X-Burger"}, {Key: "category_id", Value: categoryID}}
+	if _, err := db.Collection("products").InsertOne(ctx, product); err != nil {
+		t.Fatalf("InsertOne product: %v", err)
+	}
+
+	// Running twice checks that a rerun leaves the converted documents alone.
+	for run := 1; run <= 2; run++ {
+		if err := migration.ConvertUUIDSubtype(ctx, db); err != nil {
+			t.Fatalf("ConvertUUIDSubtype run %d: %v", run, err)
+		}
+	}
+
+	for name, want := range map[string]int64{"categories": 2, "products": 1} {
+		collection := db.Collection(name)
+		total, err := collection.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			t.Fatalf("CountDocuments %s: %v", name, err)
+		}
+		legacy := countLegacyIDs(t, collection)
+		if total != want || legacy != 0 {
+			t.Fatalf("%s has %d documents, %d with legacy identifiers, want %d and 0", name, total, legacy, want)
+		}
+	}
+
+	var converted struct {
+		CategoryID primitive.Binary `bson:"category_id"`
+	}
+	if err := db.Collection("products").FindOne(ctx, bson.M{}).Decode(&converted); err != nil {
+		t.Fatalf("FindOne product: %v", err)
+	}
+	if converted.CategoryID.Subtype != bsontype.BinaryUUID || string(converted.CategoryID.Data) != string(categoryID.Data) {
+		t.Fatalf("product category_id = %v, want %x with subtype 0x04", converted.CategoryID, categoryID.Data)
+	}
+}
+
+func TestNormalizeClientDocumentsKeepsCollidingClients(t *testing.T) {
+	ctx := context.Background()
+	db := newDatabase(t)
+
+	clients := []interface{}{
+		bson.D{{Key: "_id", Value: uuid.New()}, {Key: "cpf", Value: "52998224725"}, {Key: "mail", Value: "ana@example.com"}},
+		bson.D{{Key: "_id", Value: uuid.New()}, {Key: "cpf", Value: "529.982.247-25"}, {Key: "mail", Value: " Bruno@Example.com "}},
+	}
+	if _, err := db.Collection("clients").InsertMany(ctx, clients); err != nil {
+		t.Fatalf("InsertMany clients: %v", err)
+	}
+
+	if err := migration.NormalizeClientDocuments(ctx, db); err != nil {
+		t.Fatalf("NormalizeClientDocuments: %v", err)
+	}
+
+	var duplicate struct {
+		Cpf  string `bson:"cpf"`
+		Mail string `bson:"mail"`
+	}
+	if err := db.Collection("clients").FindOne(ctx, bson.M{"_id": clients[1].(bson.D)[0].Value}).Decode(&duplicate); err != nil {
+		t.Fatalf("the colliding client was dropped: %v", err)
+	}
+	if duplicate.Cpf != "529.982.247-25" || duplicate.Mail != "bruno@example.com" {
+		t.Fatalf("colliding client = %+v, want its CPF as stored and its e-mail normalized", duplicate)
+	}
+}
+
+func countLegacyIDs(t *testing.T, collection *mongo.Collection) int64 {
+	t.Helper()
+
+	cursor, err := collection.Find(context.Background(), bson.M{})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	var docs []struct {
+		ID primitive.Binary `bson:"_id"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		t.Fatalf("All: %v", err)
+	}
+
+	var legacy int64
+	for _, doc := range docs {
+		if doc.ID.Subtype != bsontype.BinaryUUID {
+			legacy++
+		}
+	}
+	return legacy
+}
@@ -2,24 +2,40 @@ package repository
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type OrderRepository struct {
 	Collection *mongo.Collection
+	timeout    time.Duration
 }
 
-func NewOrderRepository(db *mongo.Database) *OrderRepository {
-	return &OrderRepository{Collection: db.Collection("orders")}
+func NewOrderRepository(db *mongo.Database, timeout time.Duration) *OrderRepository {
+	return &OrderRepository{Collection: db.Collection("orders"), timeout: timeout}
+}
+
+func (pr *OrderRepository) EnsureIndexes(ctx context.Context) error {
+	return createIndexes(ctx, pr.Collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("status_created_at")},
+		{Keys: bson.D{{Key: "client", Value: 1}}, Options: options.Index().SetName("client")},
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}, Options: options.Index().SetName("created_at_id")},
+		{Keys: bson.D{{Key: "client", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}, Options: options.Index().SetName("client_created_at_id")},
+		{Keys: bson.D{{Key: "total", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("total_id")},
+		{Keys: bson.D{{Key: "items.product_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("items_product_id_created_at")},
+	})
 }
 
 func (pr *OrderRepository) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
 	_, err := pr.Collection.InsertOne(ctx, order)
 	if err != nil {
 		return nil, err
@@ -27,55 +43,183 @@ func (pr *OrderRepository) CreateOrder(ctx context.Context, order *domain.Order)
 	return order, nil
 }
 
-func (pr *OrderRepository) GetOrders(ctx context.Context, page, limit int) ([]domain.Order, error) {
+func (pr *OrderRepository) GetOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, page, limit int) ([]domain.Order, int64, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	match := orderFilter(filter)
+	total, err := pr.Collection.CountDocuments(ctx, match)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	direction := 1
+	if sort.Descending {
+		direction = -1
+	}
+
 	var orders []domain.Order
-	opts := options.Find().SetSkip(int64((page - 1) * limit)).SetLimit(int64(limit))
+	opts := options.Find().
+		SetSort(bson.D{{Key: sort.Field, Value: direction}, {Key: "_id", Value: direction}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
 
-	cursor, err := pr.Collection.Find(ctx, bson.M{}, opts)
+	cursor, err := pr.Collection.Find(ctx, match, opts)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer cursor.Close(ctx)
 
 	for cursor.Next(ctx) {
 		var order domain.Order
 		if err := cursor.Decode(&order); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		orders = append(orders, order)
 	}
 	if err := cursor.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+func (pr *OrderRepository) GetOrdersByCursor(ctx context.Context, query domain.OrderCursorQuery) ([]domain.Order, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	filter := orderFilter(query.Filter)
+
+	// Newer orders are read oldest first from the cursor and reversed below.
+	direction, position, cmp := -1, query.After, "$lt"
+	if query.Before != nil {
+		direction, position, cmp = 1, query.Before, "$gt"
+	}
+	if position != nil {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{cmp: position.CreatedAt}},
+			bson.M{"created_at": position.CreatedAt, "_id": bson.M{cmp: position.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(query.Limit))
+	cursor, err := pr.Collection.Find(ctx, filter, opts)
+	if err != nil {
 		return nil, err
 	}
 
+	var orders []domain.Order
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+	if query.Before != nil {
+		slices.Reverse(orders)
+	}
 	return orders, nil
 }
 
+// orderFilter translates the domain filter into a query on the orders collection,
+// whose field names match the sort fields of domain.OrderSort.
+func orderFilter(f domain.OrderFilter) bson.M {
+	filter := bson.M{}
+	if len(f.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": f.Statuses}
+	}
+
+	created := bson.M{}
+	if !f.CreatedFrom.IsZero() {
+		created["$gte"] = f.CreatedFrom
+	}
+	if !f.CreatedTo.IsZero() {
+		created["$lt"] = f.CreatedTo
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+
+	if f.Client != "" {
+		filter["client"] = f.Client
+	}
+
+	total := bson.M{}
+	if f.MinTotal != nil {
+		total["$gte"] = *f.MinTotal
+	}
+	if f.MaxTotal != nil {
+		total["$lte"] = *f.MaxTotal
+	}
+	if len(total) > 0 {
+		filter["total"] = total
+	}
+
+	if f.ProductID != "" {
+		filter["items.product_id"] = f.ProductID
+	}
+	return filter
+}
+
 func (pr *OrderRepository) GetOrderByID(ctx context.Context, id string) (*domain.Order, error) {
-	uuid, err := uuid.Parse(id)
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, err
 	}
 
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
-
 	var order domain.Order
-	err = pr.Collection.FindOne(ctx, bson.M{"_id": binaryUUID}).Decode(&order)
+	err = pr.Collection.FindOne(ctx, bson.M{"_id": uuidID}).Decode(&order)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "")
 	}
 	return &order, nil
 }
 
 func (pr *OrderRepository) SetStatus(ctx context.Context, id uuid.UUID, status int, description string) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
 	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{"status": status, "status_description": description}}
-	_, err := pr.Collection.UpdateOne(ctx, filter, update)
+	update := bson.M{"$set": bson.M{"status": status, "status_description": description, "updated_at": time.Now()}}
+	result, err := pr.Collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
 	return nil
 }
+
+func (pr *OrderRepository) CountByStatus(ctx context.Context) (map[int]int64, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$status"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+	}
+	cursor, err := pr.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := map[int]int64{}
+	for cursor.Next(ctx) {
+		var group struct {
+			Status int   `bson:"_id"`
+			Count  int64 `bson:"count"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return nil, err
+		}
+		counts[group.Status] = group.Count
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
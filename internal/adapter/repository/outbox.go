@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository struct {
+	Collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewOutboxRepository(db *mongo.Database, timeout time.Duration) *OutboxRepository {
+	return &OutboxRepository{Collection: db.Collection("outbox"), timeout: timeout}
+}
+
+func (r *OutboxRepository) EnsureIndexes(ctx context.Context) error {
+	return createIndexes(ctx, r.Collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "delivered_at", Value: 1}, {Key: "next_attempt_at", Value: 1}}, Options: options.Index().SetName("delivered_at_next_attempt_at")},
+	})
+}
+
+func (r *OutboxRepository) Save(ctx context.Context, events ...*domain.Event) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		documents = append(documents, domain.OutboxMessage{
+			Event:         *event,
+			NextAttemptAt: event.OccurredAt,
+		})
+	}
+
+	_, err := r.Collection.InsertMany(ctx, documents)
+	return err
+}
+
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var messages []domain.OutboxMessage
+
+	for len(messages) < limit {
+		now := time.Now()
+		filter := bson.M{"delivered_at": nil, "next_attempt_at": bson.M{"$lte": now}}
+		update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+		opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "occurred_at", Value: 1}})
+
+		var message domain.OutboxMessage
+		err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&message)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"delivered_at": time.Now(), "last_error": ""}, "$inc": bson.M{"attempts": 1}}
+	_, err := r.Collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"next_attempt_at": nextAttemptAt, "last_error": reason}, "$inc": bson.M{"attempts": 1}}
+	_, err := r.Collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
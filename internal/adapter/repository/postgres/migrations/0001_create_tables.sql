@@ -0,0 +1,101 @@
+CREATE TABLE categories (
+    id          uuid PRIMARY KEY,
+    name        text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    created_at  timestamptz NOT NULL,
+    updated_at  timestamptz NOT NULL,
+    CONSTRAINT categories_name_unique UNIQUE (name)
+);
+
+CREATE TABLE products (
+    id          uuid PRIMARY KEY,
+    category_id uuid NOT NULL REFERENCES categories (id) ON DELETE RESTRICT,
+    name        text NOT NULL,
+    price       numeric(12, 2) NOT NULL,
+    description text NOT NULL DEFAULT '',
+    image       text NOT NULL DEFAULT '',
+    created_at  timestamptz NOT NULL,
+    updated_at  timestamptz NOT NULL
+);
+
+CREATE INDEX products_category_id ON products (category_id);
+
+CREATE TABLE clients (
+    cpf        char(11) PRIMARY KEY,
+    name       text NOT NULL,
+    mail       text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+
+-- Orders keep the client CPF and a copy of the product name and price of each item,
+-- so they are not tied to later changes of clients and products.
+CREATE TABLE orders (
+    id                 uuid PRIMARY KEY,
+    client             char(11) NOT NULL,
+    total              numeric(12, 2) NOT NULL,
+    status             integer NOT NULL,
+    status_description text NOT NULL DEFAULT '',
+    created_at         timestamptz NOT NULL,
+    updated_at         timestamptz NOT NULL
+);
+
+CREATE INDEX orders_status_created_at ON orders (status, created_at);
+CREATE INDEX orders_client ON orders (client);
+
+CREATE TABLE order_items (
+    order_id     uuid NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    position     integer NOT NULL,
+    product_id   text NOT NULL,
+    product_name text NOT NULL,
+    quantity     bigint NOT NULL,
+    price        numeric(12, 2) NOT NULL,
+    PRIMARY KEY (order_id, position)
+);
+
+CREATE TABLE outbox (
+    id              uuid PRIMARY KEY,
+    type            text NOT NULL,
+    aggregate_id    uuid NOT NULL,
+    payload         jsonb NOT NULL,
+    occurred_at     timestamptz NOT NULL,
+    attempts        integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    delivered_at    timestamptz,
+    last_error      text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX outbox_pending ON outbox (next_attempt_at) WHERE delivered_at IS NULL;
+
+CREATE TABLE webhook_subscriptions (
+    id         uuid PRIMARY KEY,
+    url        text NOT NULL,
+    secret     text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+
+CREATE TABLE webhook_subscription_event_types (
+    subscription_id uuid NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    position        integer NOT NULL,
+    event_type      text NOT NULL,
+    PRIMARY KEY (subscription_id, position)
+);
+
+CREATE INDEX webhook_subscription_event_types_event_type ON webhook_subscription_event_types (event_type);
+
+CREATE TABLE webhook_deliveries (
+    id              uuid PRIMARY KEY,
+    subscription_id uuid NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        uuid NOT NULL,
+    event_type      text NOT NULL,
+    payload         jsonb NOT NULL,
+    status          text NOT NULL,
+    next_attempt_at timestamptz NOT NULL,
+    attempts        jsonb NOT NULL DEFAULT '[]',
+    created_at      timestamptz NOT NULL,
+    updated_at      timestamptz NOT NULL
+);
+
+CREATE INDEX webhook_deliveries_status_next_attempt_at ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX webhook_deliveries_subscription_id_created_at ON webhook_deliveries (subscription_id, created_at DESC);
@@ -0,0 +1,4 @@
+-- Cursor pagination reads orders by (created_at, id), optionally of a single client.
+CREATE INDEX orders_created_at_id ON orders (created_at, id);
+CREATE INDEX orders_client_created_at_id ON orders (client, created_at, id);
+DROP INDEX orders_client;
@@ -0,0 +1,3 @@
+-- The order list can be sorted by total and filtered by a product in its items.
+CREATE INDEX orders_total_id ON orders (total, id);
+CREATE INDEX order_items_product_id ON order_items (product_id);
@@ -0,0 +1,14 @@
+-- Products are searched by their name and description, lowercased and without accents,
+-- which the repository keeps in search_text, and sorted by popularity, the number of
+-- units ordered kept in sold.
+ALTER TABLE products ADD COLUMN search_text text NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN sold bigint NOT NULL DEFAULT 0;
+
+UPDATE products SET
+    search_text = translate(lower(name || ' ' || description),
+        'áàâãäåéèêëíìîïóòôõöúùûüçñ', 'aaaaaaeeeeiiiiooooouuuucn'),
+    sold = COALESCE((SELECT SUM(quantity) FROM order_items WHERE order_items.product_id = CAST(products.id AS text)), 0);
+
+CREATE INDEX products_name_id ON products (name, id);
+CREATE INDEX products_price_id ON products (price, id);
+CREATE INDEX products_sold_id ON products (sold, id);
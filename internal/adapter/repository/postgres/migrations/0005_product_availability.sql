@@ -0,0 +1,3 @@
+-- Products are available, unavailable for a while, optionally until back_at, or discontinued.
+ALTER TABLE products ADD COLUMN availability text NOT NULL DEFAULT 'available';
+ALTER TABLE products ADD COLUMN back_at timestamptz;
@@ -0,0 +1,75 @@
+// Package postgres runs the sqlstore repositories on PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"io/fs"
+
+	"github.com/lib/pq"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlstore"
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+
+	// migrationLockKey identifies the advisory lock that keeps two replicas from migrating at the same time.
+	migrationLockKey = 7_301_934_276
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Dialect describes PostgreSQL to the sqlstore repositories.
+var Dialect = sqlstore.Dialect{
+	Migrations:     mustSub(migrationFiles, "migrations"),
+	ClaimLock:      "FOR UPDATE SKIP LOCKED",
+	TableExists:    `SELECT to_regclass($1) IS NOT NULL`,
+	IsConflict:     isConflict,
+	LockMigrations: lockMigrations,
+}
+
+// Open connects to the database described by dsn and checks that it is reachable.
+func Open(ctx context.Context, dsn string) (*sqlstore.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return sqlstore.New(db, Dialect), nil
+}
+
+func isConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == uniqueViolation || pqErr.Code == foreignKeyViolation)
+}
+
+// lockMigrations takes a session level advisory lock, waiting for other replicas to finish migrating.
+func lockMigrations(ctx context.Context, c *sql.Conn) (func(), error) {
+	if _, err := c.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		if _, err := c.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			logging.FromContext(ctx).Warn("failed to release the migration lock", "error", err)
+		}
+	}, nil
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
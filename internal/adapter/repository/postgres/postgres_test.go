@@ -0,0 +1,76 @@
+package postgres_test
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/postgres"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/repositorytest"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlstore"
+)
+
+// TestContract runs against the PostgreSQL at POSTGRES_TEST_URL, migrating a throwaway
+// schema per test case. It is skipped when the variable is not set.
+// operationTimeout bounds each repository call in the tests.
+const operationTimeout = 5 * time.Second
+
+func TestContract(t *testing.T) {
+	rawURL := os.Getenv("POSTGRES_TEST_URL")
+	if rawURL == "" {
+		t.Skip("POSTGRES_TEST_URL is not set")
+	}
+
+	ctx := context.Background()
+	admin, err := postgres.Open(ctx, rawURL)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		db := openSchema(t, admin, rawURL)
+
+		return repositorytest.Repositories{
+			Categories: sqlstore.NewCategoryRepository(db, operationTimeout),
+			Products:   sqlstore.NewProductRepository(db, operationTimeout),
+			Clients:    sqlstore.NewClientRepository(db, operationTimeout),
+			Orders:     sqlstore.NewOrderRepository(db, operationTimeout),
+		}
+	})
+}
+
+// openSchema creates and migrates an empty schema and returns a pool whose search_path points to it.
+func openSchema(t *testing.T, admin *sqlstore.DB, rawURL string) *sqlstore.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	schema := "contract_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+	if _, err := admin.ExecContext(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { admin.ExecContext(ctx, `DROP SCHEMA `+schema+` CASCADE`) })
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("POSTGRES_TEST_URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	db, err := postgres.Open(ctx, u.String())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := sqlstore.Migrate(ctx, db, false); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
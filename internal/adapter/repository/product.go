@@ -2,25 +2,74 @@ package repository
 
 import (
 	"context"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ProductRepository struct {
 	Collection *mongo.Collection
+	timeout    time.Duration
 }
 
-func NewProductRepository(db *mongo.Database) *ProductRepository {
-	return &ProductRepository{Collection: db.Collection("products")}
+// productFields are the fields a product is created or replaced with: the product and
+// its folded name and description, which the search matches like SQL databases do.
+type productFields struct {
+	domain.Product `bson:",inline"`
+	SearchText     string `bson:"search_text"`
+}
+
+func newProductFields(product *domain.Product) productFields {
+	return productFields{Product: *product, SearchText: product.SearchText()}
+}
+
+// productDocument is a product as stored, with the number of units ordered kept next
+// to it for the popularity sort. Replacing a product sets its fields only, so it
+// keeps the count.
+type productDocument struct {
+	productFields `bson:",inline"`
+	Sold          int64 `bson:"sold"`
+}
+
+// productSortFields maps the product sorts to document fields.
+var productSortFields = map[string]string{
+	domain.ProductSortCreatedAt:  "created_at",
+	domain.ProductSortName:       "name",
+	domain.ProductSortPrice:      "price",
+	domain.ProductSortPopularity: "sold",
+}
+
+func NewProductRepository(db *mongo.Database, timeout time.Duration) *ProductRepository {
+	return &ProductRepository{Collection: db.Collection("products"), timeout: timeout}
+}
+
+func (pr *ProductRepository) EnsureIndexes(ctx context.Context) error {
+	return createIndexes(ctx, pr.Collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "category_id", Value: 1}}, Options: options.Index().SetName("category_id")},
+		// Version 3 text indexes ignore case and diacritics; Portuguese adds stemming and stop words.
+		{
+			Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetName("name_description_text").
+				SetDefaultLanguage("portuguese").
+				SetWeights(bson.D{{Key: "name", Value: 3}, {Key: "description", Value: 1}}),
+		},
+		{Keys: bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("price_id")},
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("name_id")},
+		{Keys: bson.D{{Key: "sold", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("sold_id")},
+	})
 }
 
 func (pr *ProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	_, err := pr.Collection.InsertOne(ctx, product)
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	_, err := pr.Collection.InsertOne(ctx, productDocument{productFields: newProductFields(product)})
 	if err != nil {
 		return nil, err
 	}
@@ -28,143 +77,224 @@ func (pr *ProductRepository) CreateProduct(ctx context.Context, product *domain.
 }
 
 func (pr *ProductRepository) GetProductByID(ctx context.Context, id string) (*domain.Product, error) {
-	uuid, err := uuid.Parse(id)
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, err
 	}
 
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
-
 	var product domain.Product
-	err = pr.Collection.FindOne(ctx, bson.M{"_id": binaryUUID}).Decode(&product)
+	err = pr.Collection.FindOne(ctx, bson.M{"_id": uuidID}).Decode(&product)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "")
 	}
 	return &product, nil
 }
 
 func (pr *ProductRepository) ReplaceProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	uuid, err := uuid.Parse(product.ID.String())
-	if err != nil {
-		return nil, err
-	}
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
 
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
-
-	filter := bson.M{"_id": binaryUUID}
-	update := bson.M{"$set": product}
-	_, err = pr.Collection.UpdateOne(ctx, filter, update)
+	filter := bson.M{"_id": product.ID}
+	update := bson.M{"$set": newProductFields(product)}
+	result, err := pr.Collection.UpdateOne(ctx, filter, update)
 
 	if err != nil {
 		return nil, err
 	}
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrNotFound
+	}
 	return product, nil
 }
 
 func (pr *ProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	uuid, err := uuid.Parse(product.ID.String())
-	if err != nil {
-		return nil, err
-	}
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
 
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
-
-	filter := bson.M{"_id": binaryUUID}
-	update := bson.M{"$set": product}
+	filter := bson.M{"_id": product.ID}
+	set := bson.M{}
 
-	if product.CategoryId.String() != "" {
-		update["$set"].(bson.M)["category_id"] = product.CategoryId
+	if product.CategoryId != uuid.Nil {
+		set["category_id"] = product.CategoryId
 	}
 
 	if product.Name != "" {
-		update["$set"].(bson.M)["name"] = product.Name
+		set["name"] = product.Name
 	}
 
 	if product.Description != "" {
-		update["$set"].(bson.M)["description"] = product.Description
+		set["description"] = product.Description
 	}
 
 	if product.Price != 0 {
-		update["$set"].(bson.M)["price"] = product.Price
+		set["price"] = product.Price
+	}
+
+	if product.Image != "" {
+		set["image"] = product.Image
 	}
 
 	if !product.UpdatedAt.IsZero() {
-		update["$set"].(bson.M)["updated_at"] = product.UpdatedAt
+		set["updated_at"] = product.UpdatedAt
 	}
 
-	_, err = pr.Collection.UpdateOne(ctx, filter, update)
+	result, err := pr.Collection.UpdateOne(ctx, filter, bson.M{"$set": set})
 
 	if err != nil {
 		return nil, err
 	}
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	// The search text depends on both the name and the description, so it is rebuilt
+	// from the updated document.
+	if product.Name != "" || product.Description != "" {
+		var updated domain.Product
+		if err := pr.Collection.FindOne(ctx, filter).Decode(&updated); err != nil {
+			return nil, translateError(err, "")
+		}
+		if _, err := pr.Collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"search_text": updated.SearchText()}}); err != nil {
+			return nil, err
+		}
+	}
 	return product, nil
 }
 
 func (pr *ProductRepository) DeleteProduct(ctx context.Context, id string) error {
-	uuid, err := uuid.Parse(id)
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
 
-	binaryUUID := primitive.Binary{
-		Subtype: 0x00,
-		Data:    uuid[:],
-	}
-
-	_, err = pr.Collection.DeleteOne(ctx, bson.M{"_id": binaryUUID})
+	result, err := pr.Collection.DeleteOne(ctx, bson.M{"_id": uuidID})
 
 	if err != nil {
 		return err
 	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
 
 	return nil
 }
 
-func (pr *ProductRepository) GetProducts(ctx context.Context, categoryId string, page, limit int) ([]domain.Product, error) {
-	var products []domain.Product
-	opts := options.Find().SetSkip(int64((page - 1) * limit)).SetLimit(int64(limit))
-	filter := bson.M{}
-
-	if categoryId != "" {
-
-		uuid, err := uuid.Parse(categoryId)
-		if err != nil {
-			return nil, err
-		}
+func (pr *ProductRepository) GetProducts(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, page, limit int) ([]domain.Product, int64, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
 
-		binaryUUID := primitive.Binary{
-			Subtype: 0x00,
-			Data:    uuid[:],
-		}
+	match := productFilter(filter)
+	total, err := pr.Collection.CountDocuments(ctx, match)
+	if err != nil {
+		return nil, 0, err
+	}
 
-		filter["category_id"] = binaryUUID
+	field, ok := productSortFields[sort.Field]
+	if !ok {
+		field = "created_at"
 	}
+	direction := 1
+	if sort.Descending {
+		direction = -1
+	}
+
+	var products []domain.Product
+	opts := options.Find().
+		SetSort(bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
 
-	cursor, err := pr.Collection.Find(ctx, filter, opts)
+	cursor, err := pr.Collection.Find(ctx, match, opts)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer cursor.Close(ctx)
 
 	for cursor.Next(ctx) {
 		var product domain.Product
 		if err = cursor.Decode(&product); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		products = append(products, product)
 	}
 	if err := cursor.Err(); err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+// productFilter translates filter to a query. The text index finds the products with any
+// of the search words, so the search does not scan the collection; of those, only the ones
+// whose search text contains every term are kept, as in SQL databases.
+func productFilter(f domain.ProductFilter) bson.M {
+	filter := bson.M{}
+	if terms := f.SearchTerms(); len(terms) > 0 {
+		filter["$text"] = bson.M{"$search": strings.Join(terms, " "), "$language": "portuguese"}
+		contains := make(bson.A, len(terms))
+		for i, term := range terms {
+			contains[i] = bson.M{"search_text": bson.M{"$regex": regexp.QuoteMeta(term)}}
+		}
+		filter["$and"] = contains
+	}
+
+	if len(f.CategoryIDs) > 0 {
+		filter["category_id"] = bson.M{"$in": f.CategoryIDs}
+	}
+
+	price := bson.M{}
+	if f.MinPrice != nil {
+		price["$gte"] = *f.MinPrice
+	}
+	if f.MaxPrice != nil {
+		price["$lte"] = *f.MaxPrice
+	}
+	if len(price) > 0 {
+		filter["price"] = price
 	}
 
-	return products, nil
+	if !f.AvailableAt.IsZero() {
+		filter["$or"] = bson.A{
+			bson.M{"availability.status": bson.M{"$nin": bson.A{domain.AvailabilityUnavailable, domain.AvailabilityDiscontinued}}},
+			bson.M{"availability.status": domain.AvailabilityUnavailable, "availability.back_at": bson.M{"$lte": f.AvailableAt}},
+		}
+	}
+	return filter
+}
+
+func (pr *ProductRepository) SetAvailability(ctx context.Context, id uuid.UUID, availability domain.Availability) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	result, err := pr.Collection.UpdateOne(ctx, bson.M{"_id": id},
+		bson.M{"$set": bson.M{"availability": availability, "updated_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (pr *ProductRepository) RecordSales(ctx context.Context, items []domain.OrderItem) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	for _, item := range items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return err
+		}
+		if _, err := pr.Collection.UpdateOne(ctx, bson.M{"_id": productID}, bson.M{"$inc": bson.M{"sold": item.Quantity}}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
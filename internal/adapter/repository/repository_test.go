@@ -0,0 +1,105 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/repositorytest"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestContract runs against the MongoDB at MONGO_TEST_URI, creating a throwaway
+// database per test case. It is skipped when the variable is not set.
+// operationTimeout bounds each repository call in the tests.
+const operationTimeout = 5 * time.Second
+
+func TestContract(t *testing.T) {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(repository.NewRegistry()))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(ctx) })
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		db := client.Database("contract_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16])
+		t.Cleanup(func() { db.Drop(ctx) })
+
+		categories := repository.NewCategoryRepository(db, operationTimeout)
+		products := repository.NewProductRepository(db, operationTimeout)
+		clients := repository.NewClientRepository(db, operationTimeout)
+		orders := repository.NewOrderRepository(db, operationTimeout)
+		if err := repository.EnsureIndexes(ctx, categories, products, clients, orders); err != nil {
+			t.Fatalf("EnsureIndexes: %v", err)
+		}
+
+		return repositorytest.Repositories{
+			Categories: categories,
+			Products:   products,
+			Clients:    clients,
+			Orders:     orders,
+		}
+	})
+}
+
+// TestUnitOfWorkRollsBack needs the MongoDB at MONGO_TEST_URI to run as a replica set.
+func TestUnitOfWorkRollsBack(t *testing.T) {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(repository.NewRegistry()))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(ctx) })
+
+	uow, err := repository.NewUnitOfWork(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !uow.Transactional() {
+		t.Skip("MONGO_TEST_URI is not a replica set")
+	}
+
+	db := client.Database("uow_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16])
+	t.Cleanup(func() { db.Drop(ctx) })
+	categories := repository.NewCategoryRepository(db, operationTimeout)
+	if err := repository.EnsureIndexes(ctx, categories); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+
+	category, err := domain.NewCategory("Lanche", "Hamburgers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("boom")
+	err = uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := categories.CreateCategory(ctx, category); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Do = %v, want %v", err, failure)
+	}
+
+	if _, err := categories.GetCategoryByID(ctx, category.ID.String()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("category written by the failed unit is still visible: %v", err)
+	}
+}
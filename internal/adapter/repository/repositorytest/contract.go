@@ -0,0 +1,768 @@
+// Package repositorytest holds the behaviour every implementation of the repository
+// ports must share. Adapters run it from their own tests with a factory that returns
+// empty repositories.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+// Repositories groups the repositories under test. The factory must return empty
+// repositories, with any unique indexes already in place.
+type Repositories struct {
+	Categories port.CategoryRepository
+	Products   port.ProductRepository
+	Clients    port.ClientRepository
+	Orders     port.OrderRepository
+}
+
+type Factory func(t *testing.T) Repositories
+
+// Run executes the whole contract, calling newRepositories once per test case.
+func Run(t *testing.T, newRepositories Factory) {
+	t.Run("Category", func(t *testing.T) { RunCategory(t, newRepositories) })
+	t.Run("Product", func(t *testing.T) { RunProduct(t, newRepositories) })
+	t.Run("Client", func(t *testing.T) { RunClient(t, newRepositories) })
+	t.Run("Order", func(t *testing.T) { RunOrder(t, newRepositories) })
+}
+
+func RunCategory(t *testing.T, newRepositories Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepositories(t).Categories
+		category := newCategory(t, "Lanche")
+
+		if _, err := repo.CreateCategory(ctx, category); err != nil {
+			t.Fatalf("CreateCategory: %v", err)
+		}
+
+		got, err := repo.GetCategoryByID(ctx, category.ID.String())
+		if err != nil {
+			t.Fatalf("GetCategoryByID: %v", err)
+		}
+		if got.ID != category.ID || got.Name != category.Name || got.Description != category.Description {
+			t.Fatalf("GetCategoryByID = %+v, want %+v", got, category)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		repo := newRepositories(t).Categories
+
+		_, err := repo.GetCategoryByID(ctx, uuid.NewString())
+		assertNotFound(t, err)
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		repo := newRepositories(t).Categories
+		mustCreateCategory(t, repo, "Lanche")
+
+		_, err := repo.CreateCategory(ctx, newCategory(t, "Lanche"))
+		assertConflict(t, err)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		repo := newRepositories(t).Categories
+		names := []string{"Lanche", "Bebida", "Acompanhamento", "Sobremesa", "Combo"}
+		for _, name := range names {
+			mustCreateCategory(t, repo, name)
+		}
+
+		seen := map[uuid.UUID]bool{}
+		for page := 1; page <= 3; page++ {
+			categories, total, err := repo.GetCategories(ctx, page, 2)
+			if err != nil {
+				t.Fatalf("GetCategories page %d: %v", page, err)
+			}
+			if total != int64(len(names)) {
+				t.Fatalf("GetCategories page %d total = %d, want %d", page, total, len(names))
+			}
+			if want := min(2, len(names)-(page-1)*2); len(categories) != want {
+				t.Fatalf("GetCategories page %d returned %d categories, want %d", page, len(categories), want)
+			}
+			for _, category := range categories {
+				if seen[category.ID] {
+					t.Fatalf("category %s returned on more than one page", category.ID)
+				}
+				seen[category.ID] = true
+			}
+		}
+		if len(seen) != len(names) {
+			t.Fatalf("pages returned %d distinct categories, want %d", len(seen), len(names))
+		}
+
+		categories, total, err := repo.GetCategories(ctx, 4, 2)
+		if err != nil {
+			t.Fatalf("GetCategories past the last page: %v", err)
+		}
+		if len(categories) != 0 || total != int64(len(names)) {
+			t.Fatalf("GetCategories past the last page returned %d categories of %d, want 0 of %d", len(categories), total, len(names))
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		repo := newRepositories(t).Categories
+		category := mustCreateCategory(t, repo, "Lanche")
+
+		category.Name = "Lanches"
+		category.Description = "Hamburgers"
+		if _, err := repo.ReplaceCategory(ctx, category); err != nil {
+			t.Fatalf("ReplaceCategory: %v", err)
+		}
+
+		got, err := repo.GetCategoryByID(ctx, category.ID.String())
+		if err != nil {
+			t.Fatalf("GetCategoryByID: %v", err)
+		}
+		if got.Name != "Lanches" || got.Description != "Hamburgers" {
+			t.Fatalf("GetCategoryByID after replace = %+v", got)
+		}
+
+		_, err = repo.ReplaceCategory(ctx, newCategory(t, "Bebida"))
+		assertNotFound(t, err)
+	})
+
+	t.Run("ReplaceDuplicateName", func(t *testing.T) {
+		repo := newRepositories(t).Categories
+		mustCreateCategory(t, repo, "Lanche")
+		category := mustCreateCategory(t, repo, "Bebida")
+
+		category.Name = "Lanche"
+		_, err := repo.ReplaceCategory(ctx, category)
+		assertConflict(t, err)
+	})
+
+	t.Run("UpdateKeepsEmptyFields", func(t *testing.T) {
+		repo := newRepositories(t).Categories
+		category := mustCreateCategory(t, repo, "Lanche")
+
+		if _, err := repo.UpdateCategory(ctx, &domain.Category{ID: category.ID, Description: "Hamburgers"}); err != nil {
+			t.Fatalf("UpdateCategory: %v", err)
+		}
+
+		got, err := repo.GetCategoryByID(ctx, category.ID.String())
+		if err != nil {
+			t.Fatalf("GetCategoryByID: %v", err)
+		}
+		if got.Name != "Lanche" || got.Description != "Hamburgers" {
+			t.Fatalf("GetCategoryByID after update = %+v", got)
+		}
+
+		_, err = repo.UpdateCategory(ctx, &domain.Category{ID: uuid.New(), Description: "Hamburgers"})
+		assertNotFound(t, err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepositories(t).Categories
+		category := mustCreateCategory(t, repo, "Lanche")
+
+		if err := repo.DeleteCategory(ctx, category.ID.String()); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+
+		_, err := repo.GetCategoryByID(ctx, category.ID.String())
+		assertNotFound(t, err)
+		assertNotFound(t, repo.DeleteCategory(ctx, category.ID.String()))
+	})
+}
+
+// RunProduct creates the categories products refer to first, as adapters may enforce the relation.
+func RunProduct(t *testing.T, newRepositories Factory) {
+	ctx := context.Background()
+
+	newProductRepository := func(t *testing.T) (port.ProductRepository, uuid.UUID, uuid.UUID) {
+		repos := newRepositories(t)
+		return repos.Products, mustCreateCategory(t, repos.Categories, "Lanche").ID, mustCreateCategory(t, repos.Categories, "Bebida").ID
+	}
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo, categoryID, _ := newProductRepository(t)
+		product := newProduct(t, "X-Burger", 25.5, categoryID)
+
+		if _, err := repo.CreateProduct(ctx, product); err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+
+		got, err := repo.GetProductByID(ctx, product.ID.String())
+		if err != nil {
+			t.Fatalf("GetProductByID: %v", err)
+		}
+		if got.ID != product.ID || got.Name != product.Name || got.Price != product.Price || got.CategoryId != categoryID {
+			t.Fatalf("GetProductByID = %+v, want %+v", got, product)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		repo, _, _ := newProductRepository(t)
+
+		_, err := repo.GetProductByID(ctx, uuid.NewString())
+		assertNotFound(t, err)
+	})
+
+	t.Run("FilterByCategory", func(t *testing.T) {
+		repo, categoryID, otherCategoryID := newProductRepository(t)
+		mustCreateProduct(t, repo, "X-Burger", 25.5, categoryID)
+		mustCreateProduct(t, repo, "X-Salada", 27, categoryID)
+		mustCreateProduct(t, repo, "Refrigerante", 7, otherCategoryID)
+
+		products, total, err := repo.GetProducts(ctx, domain.ProductFilter{CategoryIDs: []uuid.UUID{categoryID}}, domain.ProductSort{}, 1, 10)
+		if err != nil {
+			t.Fatalf("GetProducts: %v", err)
+		}
+		if len(products) != 2 || total != 2 {
+			t.Fatalf("GetProducts by category returned %d products of %d, want 2 of 2", len(products), total)
+		}
+		for _, product := range products {
+			if product.CategoryId != categoryID {
+				t.Fatalf("GetProducts by category returned product of category %s", product.CategoryId)
+			}
+		}
+
+		products, _, err = repo.GetProducts(ctx, domain.ProductFilter{}, domain.ProductSort{}, 1, 10)
+		if err != nil {
+			t.Fatalf("GetProducts: %v", err)
+		}
+		if len(products) != 3 {
+			t.Fatalf("GetProducts returned %d products, want 3", len(products))
+		}
+
+		products, total, err = repo.GetProducts(ctx, domain.ProductFilter{}, domain.ProductSort{}, 2, 2)
+		if err != nil {
+			t.Fatalf("GetProducts page 2: %v", err)
+		}
+		if len(products) != 1 || total != 3 {
+			t.Fatalf("GetProducts page 2 returned %d products of %d, want 1 of 3", len(products), total)
+		}
+	})
+
+	t.Run("SearchAndSort", func(t *testing.T) {
+		repo, snackID, drinkID := newProductRepository(t)
+		var products []*domain.Product
+		for _, p := range []struct {
+			name, description string
+			price             float64
+			categoryID        uuid.UUID
+		}{
+			{"Pão de Queijo", "Tradicional mineiro", 8.5, snackID},
+			{"X-Burger", "Pão, hambúrguer e queijo", 25.5, snackID},
+			{"Refrigerante", "Lata", 7, drinkID},
+			{"Suco de laranja", "Natural", 9, drinkID},
+		} {
+			product := newProduct(t, p.name, p.price, p.categoryID)
+			product.Description = p.description
+			if _, err := repo.CreateProduct(ctx, product); err != nil {
+				t.Fatalf("CreateProduct: %v", err)
+			}
+			products = append(products, product)
+		}
+		other := uuid.NewString()
+		err := repo.RecordSales(ctx, []domain.OrderItem{
+			{ProductID: products[1].ID.String(), Quantity: 3},
+			{ProductID: products[2].ID.String(), Quantity: 1},
+			{ProductID: products[2].ID.String(), Quantity: 1},
+			{ProductID: other, Quantity: 5},
+		})
+		if err != nil {
+			t.Fatalf("RecordSales: %v", err)
+		}
+
+		minPrice, maxPrice := 8.0, 9.0
+		tests := []struct {
+			name   string
+			filter domain.ProductFilter
+			sort   domain.ProductSort
+			want   []*domain.Product
+		}{
+			{"SearchIgnoresAccents", domain.ProductFilter{Search: "pao"}, domain.ProductSort{Field: domain.ProductSortName}, []*domain.Product{products[0], products[1]}},
+			{"SearchDescription", domain.ProductFilter{Search: "HAMBURGUER"}, domain.ProductSort{}, products[1:2]},
+			// Sorted by name, a product matching only some of the words would come first.
+			{"SearchAllWords", domain.ProductFilter{Search: "x queijo"}, domain.ProductSort{Field: domain.ProductSortName}, products[1:2]},
+			{"SearchPartOfWord", domain.ProductFilter{Search: "tradic MINEIRO"}, domain.ProductSort{}, products[0:1]},
+			{"Categories", domain.ProductFilter{CategoryIDs: []uuid.UUID{snackID, drinkID}, MaxPrice: &maxPrice}, domain.ProductSort{Field: domain.ProductSortPrice}, []*domain.Product{products[2], products[0], products[3]}},
+			{"PriceRange", domain.ProductFilter{MinPrice: &minPrice}, domain.ProductSort{Field: domain.ProductSortPrice, Descending: true}, []*domain.Product{products[1], products[3], products[0]}},
+			{"Name", domain.ProductFilter{}, domain.ProductSort{Field: domain.ProductSortName}, products[0:1]},
+			{"Popularity", domain.ProductFilter{}, domain.ProductSort{Field: domain.ProductSortPopularity, Descending: true}, []*domain.Product{products[1], products[2]}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, total, err := repo.GetProducts(ctx, tt.filter, tt.sort, 1, len(tt.want))
+				if err != nil {
+					t.Fatalf("GetProducts: %v", err)
+				}
+				if len(got) != len(tt.want) {
+					t.Fatalf("GetProducts returned %d products of %d, want %d", len(got), total, len(tt.want))
+				}
+				for i := range tt.want {
+					if got[i].ID != tt.want[i].ID {
+						t.Fatalf("product %d is %s, want %s", i, got[i].Name, tt.want[i].Name)
+					}
+				}
+			})
+		}
+
+		if _, err := repo.UpdateProduct(ctx, &domain.Product{ID: products[3].ID, Name: "Suco de Maçã"}); err != nil {
+			t.Fatalf("UpdateProduct: %v", err)
+		}
+		got, _, err := repo.GetProducts(ctx, domain.ProductFilter{Search: "maca"}, domain.ProductSort{}, 1, 10)
+		if err != nil {
+			t.Fatalf("GetProducts: %v", err)
+		}
+		if len(got) != 1 || got[0].ID != products[3].ID {
+			t.Fatalf("GetProducts after renaming returned %v, want the renamed product", got)
+		}
+	})
+
+	t.Run("Availability", func(t *testing.T) {
+		repo, categoryID, _ := newProductRepository(t)
+		now := time.Now().Truncate(time.Millisecond)
+		soon, later := now.Add(time.Hour), now.Add(2*time.Hour)
+
+		available := mustCreateProduct(t, repo, "X-Burger", 25.5, categoryID)
+		backSoon := mustCreateProduct(t, repo, "X-Bacon", 29, categoryID)
+		outOfStock := mustCreateProduct(t, repo, "X-Egg", 27, categoryID)
+		discontinued := mustCreateProduct(t, repo, "X-Tudo", 35, categoryID)
+		for id, availability := range map[uuid.UUID]domain.Availability{
+			backSoon.ID:     {Status: domain.AvailabilityUnavailable, BackAt: &soon},
+			outOfStock.ID:   {Status: domain.AvailabilityUnavailable},
+			discontinued.ID: {Status: domain.AvailabilityDiscontinued},
+		} {
+			if err := repo.SetAvailability(ctx, id, availability); err != nil {
+				t.Fatalf("SetAvailability: %v", err)
+			}
+		}
+		assertNotFound(t, repo.SetAvailability(ctx, uuid.New(), domain.Availability{Status: domain.AvailabilityAvailable}))
+
+		got, err := repo.GetProductByID(ctx, backSoon.ID.String())
+		if err != nil {
+			t.Fatalf("GetProductByID: %v", err)
+		}
+		if got.Availability.Status != domain.AvailabilityUnavailable || got.Availability.BackAt == nil || !got.Availability.BackAt.Equal(soon) {
+			t.Fatalf("GetProductByID availability = %+v, want unavailable until %s", got.Availability, soon)
+		}
+
+		for _, tt := range []struct {
+			at   time.Time
+			want []*domain.Product
+		}{
+			{now, []*domain.Product{available}},
+			{later, []*domain.Product{available, backSoon}},
+		} {
+			products, total, err := repo.GetProducts(ctx, domain.ProductFilter{AvailableAt: tt.at}, domain.ProductSort{Field: domain.ProductSortPrice}, 1, 10)
+			if err != nil {
+				t.Fatalf("GetProducts: %v", err)
+			}
+			if len(products) != len(tt.want) || total != int64(len(tt.want)) {
+				t.Fatalf("GetProducts available at %s returned %d products of %d, want %d", tt.at, len(products), total, len(tt.want))
+			}
+			for i := range tt.want {
+				if products[i].ID != tt.want[i].ID {
+					t.Fatalf("product %d is %s, want %s", i, products[i].Name, tt.want[i].Name)
+				}
+			}
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		repo, categoryID, _ := newProductRepository(t)
+		product := mustCreateProduct(t, repo, "X-Burger", 25.5, categoryID)
+
+		product.Name = "X-Bacon"
+		product.Price = 29.9
+		if _, err := repo.ReplaceProduct(ctx, product); err != nil {
+			t.Fatalf("ReplaceProduct: %v", err)
+		}
+
+		got, err := repo.GetProductByID(ctx, product.ID.String())
+		if err != nil {
+			t.Fatalf("GetProductByID: %v", err)
+		}
+		if got.Name != "X-Bacon" || got.Price != 29.9 {
+			t.Fatalf("GetProductByID after replace = %+v", got)
+		}
+
+		_, err = repo.ReplaceProduct(ctx, newProduct(t, "X-Salada", 27, categoryID))
+		assertNotFound(t, err)
+	})
+
+	t.Run("UpdateKeepsEmptyFields", func(t *testing.T) {
+		repo, categoryID, _ := newProductRepository(t)
+		product := mustCreateProduct(t, repo, "X-Burger", 25.5, categoryID)
+
+		if _, err := repo.UpdateProduct(ctx, &domain.Product{ID: product.ID, Price: 30}); err != nil {
+			t.Fatalf("UpdateProduct: %v", err)
+		}
+
+		got, err := repo.GetProductByID(ctx, product.ID.String())
+		if err != nil {
+			t.Fatalf("GetProductByID: %v", err)
+		}
+		if got.Name != "X-Burger" || got.Price != 30 || got.CategoryId != categoryID {
+			t.Fatalf("GetProductByID after update = %+v", got)
+		}
+
+		_, err = repo.UpdateProduct(ctx, &domain.Product{ID: uuid.New(), Price: 30})
+		assertNotFound(t, err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo, categoryID, _ := newProductRepository(t)
+		product := mustCreateProduct(t, repo, "X-Burger", 25.5, categoryID)
+
+		if err := repo.DeleteProduct(ctx, product.ID.String()); err != nil {
+			t.Fatalf("DeleteProduct: %v", err)
+		}
+
+		_, err := repo.GetProductByID(ctx, product.ID.String())
+		assertNotFound(t, err)
+		assertNotFound(t, repo.DeleteProduct(ctx, product.ID.String()))
+	})
+}
+
+func RunClient(t *testing.T, newRepositories Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepositories(t).Clients
+		client := newClient(t, "529.982.247-25")
+
+		if _, err := repo.CreateClient(ctx, client); err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+
+		got, err := repo.GetClientByCPF(ctx, client.Cpf)
+		if err != nil {
+			t.Fatalf("GetClientByCPF: %v", err)
+		}
+		if got.Cpf != client.Cpf || got.Name != client.Name || got.Mail != client.Mail {
+			t.Fatalf("GetClientByCPF = %+v, want %+v", got, client)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		repo := newRepositories(t).Clients
+
+		_, err := repo.GetClientByCPF(ctx, newClient(t, "529.982.247-25").Cpf)
+		assertNotFound(t, err)
+	})
+
+	t.Run("DuplicateCPF", func(t *testing.T) {
+		repo := newRepositories(t).Clients
+		if _, err := repo.CreateClient(ctx, newClient(t, "529.982.247-25")); err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+
+		_, err := repo.CreateClient(ctx, newClient(t, "52998224725"))
+		assertConflict(t, err)
+	})
+}
+
+func RunOrder(t *testing.T, newRepositories Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepositories(t).Orders
+		order := newOrder(t)
+
+		if _, err := repo.CreateOrder(ctx, order); err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+
+		got, err := repo.GetOrderByID(ctx, order.ID.String())
+		if err != nil {
+			t.Fatalf("GetOrderByID: %v", err)
+		}
+		if got.ID != order.ID || got.Client != order.Client || got.Total != order.Total || got.Status != order.Status {
+			t.Fatalf("GetOrderByID = %+v, want %+v", got, order)
+		}
+		if len(got.Items) != len(order.Items) || got.Items[0] != order.Items[0] {
+			t.Fatalf("GetOrderByID items = %+v, want %+v", got.Items, order.Items)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		repo := newRepositories(t).Orders
+
+		_, err := repo.GetOrderByID(ctx, uuid.NewString())
+		assertNotFound(t, err)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		repo := newRepositories(t).Orders
+		for i := 0; i < 3; i++ {
+			if _, err := repo.CreateOrder(ctx, newOrder(t)); err != nil {
+				t.Fatalf("CreateOrder: %v", err)
+			}
+		}
+
+		seen := map[uuid.UUID]bool{}
+		for page, want := range []int{2, 1} {
+			orders, total, err := repo.GetOrders(ctx, domain.OrderFilter{}, domain.OrderSort{Field: domain.OrderSortCreatedAt}, page+1, 2)
+			if err != nil {
+				t.Fatalf("GetOrders page %d: %v", page+1, err)
+			}
+			if len(orders) != want || total != 3 {
+				t.Fatalf("GetOrders page %d returned %d orders of %d, want %d of 3", page+1, len(orders), total, want)
+			}
+			for _, order := range orders {
+				seen[order.ID] = true
+			}
+		}
+		if len(seen) != 3 {
+			t.Fatalf("pages returned %d distinct orders, want 3", len(seen))
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		repo := newRepositories(t).Orders
+
+		base := time.Now().UTC().Truncate(time.Millisecond)
+		product := uuid.NewString()
+		var orders []*domain.Order
+		for i, status := range []int{1, 2, 2, 4} {
+			order := newOrder(t)
+			order.Status = status
+			order.Total = float64(10 * (i + 1))
+			order.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+			if i%2 == 1 {
+				order.Items = append(order.Items, domain.OrderItem{ProductID: product, ProductName: "Batata", Quantity: 1, Price: 9})
+			}
+			if i == 3 {
+				order.Client = "11144477735"
+			}
+			if _, err := repo.CreateOrder(ctx, order); err != nil {
+				t.Fatalf("CreateOrder: %v", err)
+			}
+			orders = append(orders, order)
+		}
+
+		minTotal, maxTotal := 15.0, 40.0
+		tests := []struct {
+			name   string
+			filter domain.OrderFilter
+			sort   domain.OrderSort
+			want   []*domain.Order
+		}{
+			{"Statuses", domain.OrderFilter{Statuses: []int{2, 4}}, domain.OrderSort{Field: domain.OrderSortCreatedAt}, orders[1:]},
+			{"CreatedRange", domain.OrderFilter{CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(3 * time.Hour)}, domain.OrderSort{Field: domain.OrderSortCreatedAt}, orders[1:3]},
+			{"Client", domain.OrderFilter{Client: "11144477735"}, domain.OrderSort{Field: domain.OrderSortCreatedAt}, orders[3:]},
+			{"TotalRange", domain.OrderFilter{MinTotal: &minTotal, MaxTotal: &maxTotal}, domain.OrderSort{Field: domain.OrderSortTotal, Descending: true}, []*domain.Order{orders[3], orders[2], orders[1]}},
+			{"Product", domain.OrderFilter{ProductID: product}, domain.OrderSort{Field: domain.OrderSortStatus, Descending: true}, []*domain.Order{orders[3], orders[1]}},
+			{"Combined", domain.OrderFilter{Statuses: []int{2}, ProductID: product}, domain.OrderSort{Field: domain.OrderSortTotal}, orders[1:2]},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, total, err := repo.GetOrders(ctx, tt.filter, tt.sort, 1, 10)
+				if err != nil {
+					t.Fatalf("GetOrders: %v", err)
+				}
+				if len(got) != len(tt.want) || total != int64(len(tt.want)) {
+					t.Fatalf("GetOrders returned %d orders of %d, want %d", len(got), total, len(tt.want))
+				}
+				for i := range tt.want {
+					if got[i].ID != tt.want[i].ID {
+						t.Fatalf("order %d is %s, want %s", i, got[i].ID, tt.want[i].ID)
+					}
+				}
+			})
+		}
+
+		page, err := repo.GetOrdersByCursor(ctx, domain.OrderCursorQuery{Filter: domain.OrderFilter{ProductID: product}, Limit: 10})
+		if err != nil {
+			t.Fatalf("GetOrdersByCursor: %v", err)
+		}
+		if len(page) != 2 || page[0].ID != orders[3].ID || page[1].ID != orders[1].ID {
+			t.Fatalf("GetOrdersByCursor with product filter returned %v, want orders 3 and 1", page)
+		}
+	})
+
+	t.Run("CursorPagination", func(t *testing.T) {
+		repo := newRepositories(t).Orders
+
+		// Two orders share a timestamp, so the ID has to break the tie.
+		base := time.Now().UTC().Truncate(time.Millisecond)
+		var want []domain.Order
+		for _, offset := range []time.Duration{0, time.Second, time.Second, 2 * time.Second, 3 * time.Second} {
+			order := newOrder(t)
+			order.CreatedAt = base.Add(offset)
+			if _, err := repo.CreateOrder(ctx, order); err != nil {
+				t.Fatalf("CreateOrder: %v", err)
+			}
+			want = append(want, *order)
+		}
+		other := newOrder(t)
+		other.Client = "11144477735"
+		other.CreatedAt = base.Add(time.Second)
+		if _, err := repo.CreateOrder(ctx, other); err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		sort.Slice(want, func(i, j int) bool {
+			return domain.NewOrderCursor(want[i]).Precedes(domain.NewOrderCursor(want[j]))
+		})
+
+		var got []domain.Order
+		query := domain.OrderCursorQuery{Filter: domain.OrderFilter{Client: "52998224725"}, Limit: 2}
+		for {
+			orders, err := repo.GetOrdersByCursor(ctx, query)
+			if err != nil {
+				t.Fatalf("GetOrdersByCursor: %v", err)
+			}
+			if len(orders) == 0 {
+				break
+			}
+			got = append(got, orders...)
+			last := domain.NewOrderCursor(orders[len(orders)-1])
+			query.After = &last
+		}
+		if len(got) != len(want) {
+			t.Fatalf("pages returned %d orders, want %d", len(got), len(want))
+		}
+		for i := range want {
+			if got[i].ID != want[i].ID {
+				t.Fatalf("order %d is %s, want %s", i, got[i].ID, want[i].ID)
+			}
+		}
+
+		position := domain.NewOrderCursor(got[3])
+		before, err := repo.GetOrdersByCursor(ctx, domain.OrderCursorQuery{Filter: domain.OrderFilter{Client: "52998224725"}, Before: &position, Limit: 2})
+		if err != nil {
+			t.Fatalf("GetOrdersByCursor before: %v", err)
+		}
+		if len(before) != 2 || before[0].ID != want[1].ID || before[1].ID != want[2].ID {
+			t.Fatalf("GetOrdersByCursor before order 3 returned %v, want orders 1 and 2", before)
+		}
+
+		all, err := repo.GetOrdersByCursor(ctx, domain.OrderCursorQuery{Limit: 10})
+		if err != nil {
+			t.Fatalf("GetOrdersByCursor without client: %v", err)
+		}
+		if len(all) != len(want)+1 {
+			t.Fatalf("GetOrdersByCursor without client returned %d orders, want %d", len(all), len(want)+1)
+		}
+	})
+
+	t.Run("SetStatus", func(t *testing.T) {
+		repo := newRepositories(t).Orders
+		order := newOrder(t)
+		if _, err := repo.CreateOrder(ctx, order); err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+
+		if err := repo.SetStatus(ctx, order.ID, 2, "Em preparação"); err != nil {
+			t.Fatalf("SetStatus: %v", err)
+		}
+
+		got, err := repo.GetOrderByID(ctx, order.ID.String())
+		if err != nil {
+			t.Fatalf("GetOrderByID: %v", err)
+		}
+		if got.Status != 2 || got.StatusDescription != "Em preparação" {
+			t.Fatalf("GetOrderByID after SetStatus = %+v", got)
+		}
+
+		assertNotFound(t, repo.SetStatus(ctx, uuid.New(), 2, "Em preparação"))
+	})
+
+	t.Run("CountByStatus", func(t *testing.T) {
+		repo := newRepositories(t).Orders
+		for _, status := range []int{1, 1, 3} {
+			order := newOrder(t)
+			order.Status = status
+			if _, err := repo.CreateOrder(ctx, order); err != nil {
+				t.Fatalf("CreateOrder: %v", err)
+			}
+		}
+
+		counts, err := repo.CountByStatus(ctx)
+		if err != nil {
+			t.Fatalf("CountByStatus: %v", err)
+		}
+		if len(counts) != 2 || counts[1] != 2 || counts[3] != 1 {
+			t.Fatalf("CountByStatus = %v, want map[1:2 3:1]", counts)
+		}
+	})
+}
+
+func newCategory(t *testing.T, name string) *domain.Category {
+	t.Helper()
+
+	category, err := domain.NewCategory(name, name+" description")
+	if err != nil {
+		t.Fatalf("NewCategory: %v", err)
+	}
+	return category
+}
+
+func mustCreateCategory(t *testing.T, repo port.CategoryRepository, name string) *domain.Category {
+	t.Helper()
+
+	category := newCategory(t, name)
+	if _, err := repo.CreateCategory(context.Background(), category); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	return category
+}
+
+func newProduct(t *testing.T, name string, price float64, categoryID uuid.UUID) *domain.Product {
+	t.Helper()
+
+	product, err := domain.NewProduct(name, price, categoryID, name+" description", "https://example.com/image.png")
+	if err != nil {
+		t.Fatalf("NewProduct: %v", err)
+	}
+	return product
+}
+
+func mustCreateProduct(t *testing.T, repo port.ProductRepository, name string, price float64, categoryID uuid.UUID) *domain.Product {
+	t.Helper()
+
+	product := newProduct(t, name, price, categoryID)
+	if _, err := repo.CreateProduct(context.Background(), product); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	return product
+}
+
+func newClient(t *testing.T, cpf string) *domain.Client {
+	t.Helper()
+
+	client, err := domain.NewClient("Maria", cpf, "maria@example.com")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func newOrder(t *testing.T) *domain.Order {
+	t.Helper()
+
+	items := []domain.OrderItem{{ProductID: uuid.NewString(), ProductName: "X-Burger", Quantity: 2, Price: 51}}
+	order, err := domain.NewOrder("529.982.247-25", items, 1, 51, "Recebido")
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+	return order
+}
+
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("err = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+func assertConflict(t *testing.T, err error) {
+	t.Helper()
+
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("err = %v, want %v", err, domain.ErrConflict)
+	}
+}
@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlstore"
+)
+
+// OnlineBackup implements port.DatabaseBackup with VACUUM INTO, which copies a
+// consistent snapshot of the database while it keeps serving reads and writes.
+type OnlineBackup struct {
+	db *sqlstore.DB
+}
+
+func NewOnlineBackup(db *sqlstore.DB) *OnlineBackup {
+	return &OnlineBackup{db: db}
+}
+
+// Backup writes the snapshot to w. The snapshot is complete before the first byte is
+// written, so an error before that leaves w untouched.
+func (b *OnlineBackup) Backup(ctx context.Context, w io.Writer) error {
+	dir, err := os.MkdirTemp("", "skinaapis-backup-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "backup.db")
+	if _, err := b.db.ExecContext(ctx, `VACUUM INTO $1`, path); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	return err
+}
@@ -0,0 +1,101 @@
+CREATE TABLE categories (
+    id          TEXT PRIMARY KEY,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL,
+    CONSTRAINT categories_name_unique UNIQUE (name)
+);
+
+CREATE TABLE products (
+    id          TEXT PRIMARY KEY,
+    category_id TEXT NOT NULL REFERENCES categories (id) ON DELETE RESTRICT,
+    name        TEXT NOT NULL,
+    price       REAL NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    image       TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX products_category_id ON products (category_id);
+
+CREATE TABLE clients (
+    cpf        TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    mail       TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Orders keep the client CPF and a copy of the product name and price of each item,
+-- so they are not tied to later changes of clients and products.
+CREATE TABLE orders (
+    id                 TEXT PRIMARY KEY,
+    client             TEXT NOT NULL,
+    total              REAL NOT NULL,
+    status             INTEGER NOT NULL,
+    status_description TEXT NOT NULL DEFAULT '',
+    created_at         TIMESTAMP NOT NULL,
+    updated_at         TIMESTAMP NOT NULL
+);
+
+CREATE INDEX orders_status_created_at ON orders (status, created_at);
+CREATE INDEX orders_client ON orders (client);
+
+CREATE TABLE order_items (
+    order_id     TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    position     INTEGER NOT NULL,
+    product_id   TEXT NOT NULL,
+    product_name TEXT NOT NULL,
+    quantity     INTEGER NOT NULL,
+    price        REAL NOT NULL,
+    PRIMARY KEY (order_id, position)
+);
+
+CREATE TABLE outbox (
+    id              TEXT PRIMARY KEY,
+    type            TEXT NOT NULL,
+    aggregate_id    TEXT NOT NULL,
+    payload         TEXT NOT NULL,
+    occurred_at     TIMESTAMP NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    delivered_at    TIMESTAMP,
+    last_error      TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX outbox_pending ON outbox (next_attempt_at) WHERE delivered_at IS NULL;
+
+CREATE TABLE webhook_subscriptions (
+    id         TEXT PRIMARY KEY,
+    url        TEXT NOT NULL,
+    secret     TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE webhook_subscription_event_types (
+    subscription_id TEXT NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    position        INTEGER NOT NULL,
+    event_type      TEXT NOT NULL,
+    PRIMARY KEY (subscription_id, position)
+);
+
+CREATE INDEX webhook_subscription_event_types_event_type ON webhook_subscription_event_types (event_type);
+
+CREATE TABLE webhook_deliveries (
+    id              TEXT PRIMARY KEY,
+    subscription_id TEXT NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL,
+    payload         TEXT NOT NULL,
+    status          TEXT NOT NULL,
+    next_attempt_at TIMESTAMP NOT NULL,
+    attempts        TEXT NOT NULL DEFAULT '[]',
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX webhook_deliveries_status_next_attempt_at ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX webhook_deliveries_subscription_id_created_at ON webhook_deliveries (subscription_id, created_at DESC);
@@ -0,0 +1,13 @@
+-- Products are searched by their name and description, lowercased and without accents,
+-- which the repository keeps in search_text, and sorted by popularity, the number of
+-- units ordered kept in sold. fold_text is registered by the sqlite package.
+ALTER TABLE products ADD COLUMN search_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN sold INTEGER NOT NULL DEFAULT 0;
+
+UPDATE products SET
+    search_text = fold_text(name || ' ' || description),
+    sold = COALESCE((SELECT SUM(quantity) FROM order_items WHERE order_items.product_id = products.id), 0);
+
+CREATE INDEX products_name_id ON products (name, id);
+CREATE INDEX products_price_id ON products (price, id);
+CREATE INDEX products_sold_id ON products (sold, id);
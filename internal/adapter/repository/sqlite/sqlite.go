@@ -0,0 +1,91 @@
+// Package sqlite runs the sqlstore repositories on an embedded SQLite database file,
+// using a pure Go driver so the API still builds as a single static binary.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"errors"
+	"io/fs"
+	"net/url"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlstore"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Dialect describes SQLite to the sqlstore repositories. SQLite serializes writers,
+// so claims need no row locks and a single process owns the migrations.
+var Dialect = sqlstore.Dialect{
+	Migrations:  mustSub(migrationFiles, "migrations"),
+	TableExists: `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = $1)`,
+	IsConflict:  isConflict,
+}
+
+// fold_text lets migrations fill the product search text the way the repository does,
+// which SQLite's ASCII-only lower cannot.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("fold_text", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		text, _ := args[0].(string)
+		return domain.FoldText(text), nil
+	})
+}
+
+// Open opens the database file at path, creating it if needed. Every connection runs
+// in WAL mode, so readers are not blocked while a request writes, with foreign keys
+// enforced. Transactions take the write lock when they begin, and writers wait for
+// each other instead of failing with SQLITE_BUSY.
+func Open(ctx context.Context, path string) (*sqlstore.DB, error) {
+	params := url.Values{
+		"_pragma": {
+			"journal_mode(WAL)",
+			"synchronous(NORMAL)",
+			"foreign_keys(1)",
+			"busy_timeout(5000)",
+		},
+		"_time_format": {"sqlite"},
+		"_txlock":      {"immediate"},
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return sqlstore.New(db, Dialect), nil
+}
+
+func isConflict(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	// ON DELETE RESTRICT violations are reported as SQLITE_CONSTRAINT_TRIGGER.
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY,
+		sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY, sqlite3.SQLITE_CONSTRAINT_TRIGGER:
+		return true
+	default:
+		return false
+	}
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,90 @@
+package sqlite_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/repositorytest"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlite"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlstore"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// operationTimeout bounds each repository call in the tests.
+const operationTimeout = 5 * time.Second
+
+func TestContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		db := open(t, filepath.Join(t.TempDir(), "skinaapis.db"))
+
+		return repositorytest.Repositories{
+			Categories: sqlstore.NewCategoryRepository(db, operationTimeout),
+			Products:   sqlstore.NewProductRepository(db, operationTimeout),
+			Clients:    sqlstore.NewClientRepository(db, operationTimeout),
+			Orders:     sqlstore.NewOrderRepository(db, operationTimeout),
+		}
+	})
+}
+
+func TestOperationTimeout(t *testing.T) {
+	db := open(t, filepath.Join(t.TempDir(), "skinaapis.db"))
+	categories := sqlstore.NewCategoryRepository(db, time.Nanosecond)
+
+	_, _, err := categories.GetCategories(context.Background(), 1, 10)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetCategories past the operation timeout = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestOnlineBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	db := open(t, filepath.Join(dir, "skinaapis.db"))
+
+	category, err := domain.NewCategory("Lanche", "Hamburgers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlstore.NewCategoryRepository(db, operationTimeout).CreateCategory(ctx, category); err != nil {
+		t.Fatal(err)
+	}
+
+	var snapshot bytes.Buffer
+	if err := sqlite.NewOnlineBackup(db).Backup(ctx, &snapshot); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	restored := filepath.Join(dir, "restored.db")
+	if err := os.WriteFile(restored, snapshot.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sqlstore.NewCategoryRepository(open(t, restored), operationTimeout).GetCategoryByID(ctx, category.ID.String())
+	if err != nil {
+		t.Fatalf("GetCategoryByID on the restored backup: %v", err)
+	}
+	if got.Name != category.Name {
+		t.Fatalf("restored category = %+v, want %+v", got, category)
+	}
+}
+
+func open(t *testing.T, path string) *sqlstore.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := sqlite.Open(ctx, path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := sqlstore.Migrate(ctx, db, false); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,140 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+const (
+	categoryConflictMessage = "a category with this name already exists"
+	categoryInUseMessage    = "the category still has products"
+	categoryColumns         = "id, name, description, created_at, updated_at"
+)
+
+type CategoryRepository struct {
+	DB      *DB
+	timeout time.Duration
+}
+
+func NewCategoryRepository(db *DB, timeout time.Duration) *CategoryRepository {
+	return &CategoryRepository{DB: db, timeout: timeout}
+}
+
+func (r *CategoryRepository) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx,
+		`INSERT INTO categories (`+categoryColumns+`) VALUES ($1, $2, $3, $4, $5)`,
+		category.ID, category.Name, category.Description, category.CreatedAt, category.UpdatedAt)
+	if err != nil {
+		return nil, r.DB.translateError(err, categoryConflictMessage)
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) GetCategoryByID(ctx context.Context, id string) (*domain.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id = $1`, uuidID)
+	category, err := scanCategory(row)
+	if err != nil {
+		return nil, r.DB.translateError(err, categoryConflictMessage)
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) GetCategories(ctx context.Context, page, limit int) ([]domain.Category, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	q := conn(ctx, r.DB)
+	total, err := count(ctx, q, `SELECT COUNT(*) FROM categories`)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT `+categoryColumns+` FROM categories ORDER BY created_at, id LIMIT $1 OFFSET $2`,
+		limit, offset(page, limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		categories = append(categories, *category)
+	}
+	return categories, total, rows.Err()
+}
+
+func (r *CategoryRepository) ReplaceCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE categories SET name = $2, description = $3, created_at = $4, updated_at = $5 WHERE id = $1`,
+		category.ID, category.Name, category.Description, category.CreatedAt, category.UpdatedAt)
+	if err != nil {
+		return nil, r.DB.translateError(err, categoryConflictMessage)
+	}
+	if err := requireAffected(result); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory changes only the fields that are set on category.
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE categories SET name = COALESCE(NULLIF($2, ''), name), description = COALESCE(NULLIF($3, ''), description) WHERE id = $1`,
+		category.ID, category.Name, category.Description)
+	if err != nil {
+		return nil, r.DB.translateError(err, categoryConflictMessage)
+	}
+	if err := requireAffected(result); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, uuidID)
+	if err != nil {
+		return r.DB.translateError(err, categoryInUseMessage)
+	}
+	return requireAffected(result)
+}
+
+func scanCategory(row rowScanner) (*domain.Category, error) {
+	var category domain.Category
+	if err := row.Scan(&category.ID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
@@ -0,0 +1,46 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+const clientConflictMessage = "a client with this CPF already exists"
+
+type ClientRepository struct {
+	DB      *DB
+	timeout time.Duration
+}
+
+func NewClientRepository(db *DB, timeout time.Duration) *ClientRepository {
+	return &ClientRepository{DB: db, timeout: timeout}
+}
+
+func (r *ClientRepository) CreateClient(ctx context.Context, client *domain.Client) (*domain.Client, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx,
+		`INSERT INTO clients (cpf, name, mail, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`,
+		string(client.Cpf), client.Name, string(client.Mail), client.CreatedAt, client.UpdatedAt)
+	if err != nil {
+		return nil, r.DB.translateError(err, clientConflictMessage)
+	}
+	return client, nil
+}
+
+func (r *ClientRepository) GetClientByCPF(ctx context.Context, cpf domain.CPF) (*domain.Client, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var client domain.Client
+	err := conn(ctx, r.DB).QueryRowContext(ctx,
+		`SELECT cpf, name, mail, created_at, updated_at FROM clients WHERE cpf = $1`, string(cpf)).
+		Scan(&client.Cpf, &client.Name, &client.Mail, &client.CreatedAt, &client.UpdatedAt)
+	if err != nil {
+		return nil, r.DB.translateError(err, clientConflictMessage)
+	}
+	return &client, nil
+}
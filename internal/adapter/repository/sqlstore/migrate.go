@@ -0,0 +1,165 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is a schema script of the dialect, named <version>_<description>.sql.
+type Migration struct {
+	Version     int
+	Description string
+	script      string
+}
+
+// Migrations returns the migrations of the dialect ordered by version.
+func (db *DB) Migrations() ([]Migration, error) {
+	names, err := fs.Glob(db.dialect.Migrations, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		prefix, description, _ := strings.Cut(strings.TrimSuffix(name, ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no numeric version prefix", name)
+		}
+
+		script, err := fs.ReadFile(db.dialect.Migrations, name)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Version:     version,
+			Description: strings.ReplaceAll(description, "_", " "),
+			script:      string(script),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrate applies the pending migrations, each in its own transaction, and returns the
+// ones applied, also when a later one fails. Applied versions are recorded in
+// schema_migrations. With dryRun set it only returns what would be applied, without
+// taking the migration lock or writing anything.
+func Migrate(ctx context.Context, db *DB, dryRun bool) ([]Migration, error) {
+	if dryRun {
+		return Pending(ctx, db)
+	}
+
+	migrations, err := db.Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	// Migration locks may belong to a session, so lock, migrate and unlock on the same connection.
+	c, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	if db.dialect.LockMigrations != nil {
+		unlock, err := db.dialect.LockMigrations(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	if _, err := c.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer PRIMARY KEY,
+		applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return nil, err
+	}
+
+	pending, err := pendingMigrations(ctx, db, c, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range pending {
+		if err := apply(ctx, c, m); err != nil {
+			return applied, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+		applied = append(applied, m)
+	}
+
+	return applied, nil
+}
+
+// Pending returns the migrations that have not been applied yet without taking the
+// migration lock, so it is cheap enough for readiness checks.
+func Pending(ctx context.Context, db *DB) ([]Migration, error) {
+	migrations, err := db.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	return pendingMigrations(ctx, db, db, migrations)
+}
+
+func pendingMigrations(ctx context.Context, db *DB, q querier, migrations []Migration) ([]Migration, error) {
+	// Nothing was applied to a database that was never migrated.
+	var exists bool
+	if err := q.QueryRowContext(ctx, db.dialect.TableExists, "schema_migrations").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return migrations, nil
+	}
+
+	applied := map[int]bool{}
+	rows, err := q.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, m := range migrations {
+		if !applied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+func apply(ctx context.Context, c *sql.Conn, m Migration) error {
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, m.script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, m.Version); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
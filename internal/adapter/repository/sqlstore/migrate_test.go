@@ -0,0 +1,113 @@
+package sqlstore_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlite"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/sqlstore"
+)
+
+// newDB opens an empty SQLite database whose dialect has the given migration scripts.
+func newDB(t *testing.T, scripts fstest.MapFS) *sqlstore.DB {
+	t.Helper()
+
+	conn, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	dialect := sqlite.Dialect
+	dialect.Migrations = scripts
+	return sqlstore.New(conn, dialect)
+}
+
+func script(sql string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(sql)}
+}
+
+func versions(migrations []sqlstore.Migration) []int {
+	versions := []int{}
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+	}
+	return versions
+}
+
+func tables(t *testing.T, db *sqlstore.DB) int {
+	t.Helper()
+	var count int
+	if err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table'`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestMigrateDryRunWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t, fstest.MapFS{
+		"1_create_a.sql": script(`CREATE TABLE a (id integer)`),
+		"2_create_b.sql": script(`CREATE TABLE b (id integer)`),
+	})
+
+	pending, err := sqlstore.Migrate(ctx, db, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(pending); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("dry run listed %v, want [1 2]", got)
+	}
+	if n := tables(t, db); n != 0 {
+		t.Fatalf("dry run created %d tables, want none", n)
+	}
+}
+
+func TestMigrateAppliesOnlyPendingMigrations(t *testing.T) {
+	ctx := context.Background()
+	scripts := fstest.MapFS{"1_create_a.sql": script(`CREATE TABLE a (id integer)`)}
+	db := newDB(t, scripts)
+
+	if applied, err := sqlstore.Migrate(ctx, db, false); err != nil || len(applied) != 1 {
+		t.Fatalf("first run applied %v, %v, want [1]", versions(applied), err)
+	}
+
+	scripts["2_create_b.sql"] = script(`CREATE TABLE b (id integer)`)
+	if pending, err := sqlstore.Migrate(ctx, db, true); err != nil || len(pending) != 1 || pending[0].Version != 2 {
+		t.Fatalf("dry run listed %v, %v, want [2]", versions(pending), err)
+	}
+	if applied, err := sqlstore.Migrate(ctx, db, false); err != nil || len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("second run applied %v, %v, want [2]", versions(applied), err)
+	}
+	if applied, err := sqlstore.Migrate(ctx, db, false); err != nil || len(applied) != 0 {
+		t.Fatalf("third run applied %v, %v, want none", versions(applied), err)
+	}
+}
+
+func TestMigrateReturnsTheMigrationsAppliedBeforeAFailure(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t, fstest.MapFS{
+		"1_create_a.sql": script(`CREATE TABLE a (id integer)`),
+		"2_broken.sql":   script(`CREATE TABLE a (id integer)`),
+		"3_create_b.sql": script(`CREATE TABLE b (id integer)`),
+	})
+
+	applied, err := sqlstore.Migrate(ctx, db, false)
+	if err == nil {
+		t.Fatal("Migrate ignored the failing migration")
+	}
+	if got := versions(applied); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("Migrate returned %v as applied, want [1]", got)
+	}
+
+	pending, err := sqlstore.Pending(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(pending); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("pending %v after the failure, want [2 3]", got)
+	}
+}
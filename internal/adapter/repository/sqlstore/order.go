@@ -0,0 +1,299 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+const orderColumns = "id, client, total, status, status_description, created_at, updated_at"
+
+type OrderRepository struct {
+	DB      *DB
+	timeout time.Duration
+}
+
+func NewOrderRepository(db *DB, timeout time.Duration) *OrderRepository {
+	return &OrderRepository{DB: db, timeout: timeout}
+}
+
+// CreateOrder inserts the order and its items in one transaction, joining the caller's unit of work if any.
+func (r *OrderRepository) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	err := NewUnitOfWork(r.DB).Do(ctx, func(ctx context.Context) error {
+		q := conn(ctx, r.DB)
+
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO orders (`+orderColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			order.ID, string(order.Client), order.Total, order.Status, order.StatusDescription, order.CreatedAt, order.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		for i, item := range order.Items {
+			_, err := q.ExecContext(ctx,
+				`INSERT INTO order_items (order_id, position, product_id, product_name, quantity, price) VALUES ($1, $2, $3, $4, $5, $6)`,
+				order.ID, i, item.ProductID, item.ProductName, item.Quantity, item.Price)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (r *OrderRepository) GetOrderByID(ctx context.Context, id string) (*domain.Order, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	q := conn(ctx, r.DB)
+	order, err := scanOrder(q.QueryRowContext(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1`, uuidID))
+	if err != nil {
+		return nil, r.DB.translateError(err, "")
+	}
+
+	orders := []domain.Order{*order}
+	if err := loadOrderItems(ctx, q, orders); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+// orderSortColumns maps the sort fields of domain.OrderSort to columns.
+var orderSortColumns = map[string]string{
+	domain.OrderSortCreatedAt: "created_at",
+	domain.OrderSortTotal:     "total",
+	domain.OrderSortStatus:    "status",
+}
+
+func (r *OrderRepository) GetOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, page, limit int) ([]domain.Order, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var args []any
+	where := whereClause(orderConditions(filter, &args))
+
+	q := conn(ctx, r.DB)
+	total, err := count(ctx, q, `SELECT COUNT(*) FROM orders`+where, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := orderSortColumns[sort.Field]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "ASC"
+	if sort.Descending {
+		direction = "DESC"
+	}
+	args = append(args, limit, offset(page, limit))
+
+	orders, err := queryOrders(ctx, q,
+		`SELECT `+orderColumns+` FROM orders`+where+
+			fmt.Sprintf(` ORDER BY %[1]s %[2]s, id %[2]s LIMIT $%[3]d OFFSET $%[4]d`, column, direction, len(args)-1, len(args)),
+		args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+func (r *OrderRepository) GetOrdersByCursor(ctx context.Context, query domain.OrderCursorQuery) ([]domain.Order, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var args []any
+	conditions := orderConditions(query.Filter, &args)
+
+	// Newer orders are read oldest first from the cursor and reversed below.
+	direction, position, cmp := "DESC", query.After, "<"
+	if query.Before != nil {
+		direction, position, cmp = "ASC", query.Before, ">"
+	}
+	if position != nil {
+		args = append(args, position.CreatedAt, position.ID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) %s ($%d, $%d)", cmp, len(args)-1, len(args)))
+	}
+	args = append(args, query.Limit)
+
+	orders, err := queryOrders(ctx, conn(ctx, r.DB),
+		`SELECT `+orderColumns+` FROM orders`+whereClause(conditions)+
+			fmt.Sprintf(` ORDER BY created_at %[1]s, id %[1]s LIMIT $%[2]d`, direction, len(args)),
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	if query.Before != nil {
+		slices.Reverse(orders)
+	}
+	return orders, nil
+}
+
+// orderConditions returns the SQL conditions of the filter, appending their arguments to args.
+func orderConditions(f domain.OrderFilter, args *[]any) []string {
+	var conditions []string
+	param := func(value any) string {
+		*args = append(*args, value)
+		return fmt.Sprintf("$%d", len(*args))
+	}
+
+	if len(f.Statuses) > 0 {
+		params := make([]string, len(f.Statuses))
+		for i, status := range f.Statuses {
+			params[i] = param(status)
+		}
+		conditions = append(conditions, `status IN (`+strings.Join(params, ", ")+`)`)
+	}
+	if !f.CreatedFrom.IsZero() {
+		conditions = append(conditions, `created_at >= `+param(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		conditions = append(conditions, `created_at < `+param(f.CreatedTo))
+	}
+	if f.Client != "" {
+		conditions = append(conditions, `client = `+param(string(f.Client)))
+	}
+	if f.MinTotal != nil {
+		conditions = append(conditions, `total >= `+param(*f.MinTotal))
+	}
+	if f.MaxTotal != nil {
+		conditions = append(conditions, `total <= `+param(*f.MaxTotal))
+	}
+	if f.ProductID != "" {
+		conditions = append(conditions,
+			`EXISTS (SELECT 1 FROM order_items WHERE order_items.order_id = orders.id AND order_items.product_id = `+param(f.ProductID)+`)`)
+	}
+	return conditions
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(conditions, ` AND `)
+}
+
+func (r *OrderRepository) SetStatus(ctx context.Context, id uuid.UUID, status int, description string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE orders SET status = $2, status_description = $3, updated_at = $4 WHERE id = $1`,
+		id, status, description, time.Now())
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *OrderRepository) CountByStatus(ctx context.Context) (map[int]int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `SELECT status, COUNT(*) FROM orders GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[int]int64{}
+	for rows.Next() {
+		var status int
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// loadOrderItems fills in the items of orders with a single query.
+// queryOrders runs a SELECT of orderColumns and loads the items of the orders it returns.
+func queryOrders(ctx context.Context, q querier, query string, args ...any) ([]domain.Order, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []domain.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadOrderItems(ctx, q, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func loadOrderItems(ctx context.Context, q querier, orders []domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	index := make(map[uuid.UUID]int, len(orders))
+	args := make([]any, len(orders))
+	for i, order := range orders {
+		index[order.ID] = i
+		args[i] = order.ID
+		orders[i].Items = []domain.OrderItem{}
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT order_id, product_id, product_name, quantity, price FROM order_items
+		WHERE order_id IN (`+placeholders(1, len(args))+`) ORDER BY order_id, position`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID uuid.UUID
+		var item domain.OrderItem
+		if err := rows.Scan(&orderID, &item.ProductID, &item.ProductName, &item.Quantity, &item.Price); err != nil {
+			return err
+		}
+		i := index[orderID]
+		orders[i].Items = append(orders[i].Items, item)
+	}
+	return rows.Err()
+}
+
+func scanOrder(row rowScanner) (*domain.Order, error) {
+	var order domain.Order
+	err := row.Scan(&order.ID, &order.Client, &order.Total, &order.Status, &order.StatusDescription, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
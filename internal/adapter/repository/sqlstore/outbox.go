@@ -0,0 +1,105 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+const outboxColumns = "id, type, aggregate_id, payload, occurred_at, attempts, next_attempt_at, delivered_at, last_error"
+
+type OutboxRepository struct {
+	DB      *DB
+	timeout time.Duration
+}
+
+func NewOutboxRepository(db *DB, timeout time.Duration) *OutboxRepository {
+	return &OutboxRepository{DB: db, timeout: timeout}
+}
+
+func (r *OutboxRepository) Save(ctx context.Context, events ...*domain.Event) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	q := conn(ctx, r.DB)
+	for _, event := range events {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO outbox (id, type, aggregate_id, payload, occurred_at, next_attempt_at) VALUES ($1, $2, $3, $4, $5, $5)`,
+			event.ID, event.Type, event.AggregateID, string(event.Payload), event.OccurredAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimPending pushes the next attempt of the claimed messages past the lease in the same
+// statement that selects them, skipping rows another relay is claiming concurrently
+// where the dialect supports it.
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	now := time.Now()
+	rows, err := conn(ctx, r.DB).QueryContext(ctx,
+		`UPDATE outbox SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM outbox WHERE delivered_at IS NULL AND next_attempt_at <= $2
+			ORDER BY occurred_at LIMIT $3 `+r.DB.dialect.ClaimLock+`
+		)
+		RETURNING `+outboxColumns,
+		now.Add(lease), now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []domain.OutboxMessage
+	for rows.Next() {
+		var message domain.OutboxMessage
+		var payload []byte
+		var deliveredAt sql.NullTime
+		err := rows.Scan(&message.ID, &message.Type, &message.AggregateID, &payload, &message.OccurredAt,
+			&message.Attempts, &message.NextAttemptAt, &deliveredAt, &message.LastError)
+		if err != nil {
+			return nil, err
+		}
+		message.Payload = payload
+		if deliveredAt.Valid {
+			message.DeliveredAt = &deliveredAt.Time
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].OccurredAt.Before(messages[j].OccurredAt)
+	})
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE outbox SET delivered_at = $2, last_error = '', attempts = attempts + 1 WHERE id = $1`,
+		id, time.Now())
+	return err
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE outbox SET next_attempt_at = $2, last_error = $3, attempts = attempts + 1 WHERE id = $1`,
+		id, nextAttemptAt, reason)
+	return err
+}
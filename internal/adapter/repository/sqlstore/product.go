@@ -0,0 +1,277 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+const (
+	productCategoryMessage = "the product category does not exist"
+	productColumns         = "id, category_id, name, price, description, image, availability, back_at, created_at, updated_at"
+)
+
+type ProductRepository struct {
+	DB      *DB
+	timeout time.Duration
+}
+
+func NewProductRepository(db *DB, timeout time.Duration) *ProductRepository {
+	return &ProductRepository{DB: db, timeout: timeout}
+}
+
+func (r *ProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx,
+		`INSERT INTO products (`+productColumns+`, search_text) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		product.ID, product.CategoryId, product.Name, product.Price, product.Description, product.Image,
+		product.Availability.Status, backAt(product.Availability), product.CreatedAt, product.UpdatedAt, product.SearchText())
+	if err != nil {
+		return nil, r.DB.translateError(err, productCategoryMessage)
+	}
+	return product, nil
+}
+
+func (r *ProductRepository) GetProductByID(ctx context.Context, id string) (*domain.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, `SELECT `+productColumns+` FROM products WHERE id = $1`, uuidID)
+	product, err := scanProduct(row)
+	if err != nil {
+		return nil, r.DB.translateError(err, productCategoryMessage)
+	}
+	return product, nil
+}
+
+// productSortColumns maps the sort fields of domain.ProductSort to columns.
+var productSortColumns = map[string]string{
+	domain.ProductSortCreatedAt:  "created_at",
+	domain.ProductSortName:       "name",
+	domain.ProductSortPrice:      "price",
+	domain.ProductSortPopularity: "sold",
+}
+
+func (r *ProductRepository) GetProducts(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, page, limit int) ([]domain.Product, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var args []any
+	where := whereClause(productConditions(filter, &args))
+
+	q := conn(ctx, r.DB)
+	total, err := count(ctx, q, `SELECT COUNT(*) FROM products`+where, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := productSortColumns[sort.Field]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "ASC"
+	if sort.Descending {
+		direction = "DESC"
+	}
+	args = append(args, limit, offset(page, limit))
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT `+productColumns+` FROM products`+where+
+			fmt.Sprintf(` ORDER BY %[1]s %[2]s, id %[2]s LIMIT $%[3]d OFFSET $%[4]d`, column, direction, len(args)-1, len(args)),
+		args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var products []domain.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		products = append(products, *product)
+	}
+	return products, total, rows.Err()
+}
+
+// productConditions returns the SQL conditions of the filter, appending their arguments
+// to args. Every search term has to appear in the folded name and description.
+func productConditions(f domain.ProductFilter, args *[]any) []string {
+	var conditions []string
+	param := func(value any) string {
+		*args = append(*args, value)
+		return fmt.Sprintf("$%d", len(*args))
+	}
+
+	for _, term := range f.SearchTerms() {
+		conditions = append(conditions, `search_text LIKE `+param("%"+likeEscaper.Replace(term)+"%")+` ESCAPE '\'`)
+	}
+	if len(f.CategoryIDs) > 0 {
+		params := make([]string, len(f.CategoryIDs))
+		for i, id := range f.CategoryIDs {
+			params[i] = param(id)
+		}
+		conditions = append(conditions, `category_id IN (`+strings.Join(params, ", ")+`)`)
+	}
+	if f.MinPrice != nil {
+		conditions = append(conditions, `price >= `+param(*f.MinPrice))
+	}
+	if f.MaxPrice != nil {
+		conditions = append(conditions, `price <= `+param(*f.MaxPrice))
+	}
+	if !f.AvailableAt.IsZero() {
+		// Like the other timestamps, back_at is written in server time, which keeps
+		// SQLite's textual comparison right.
+		conditions = append(conditions, fmt.Sprintf(`(availability = '%s' OR (availability = '%s' AND back_at <= %s))`,
+			domain.AvailabilityAvailable, domain.AvailabilityUnavailable, param(f.AvailableAt.Local())))
+	}
+	return conditions
+}
+
+// likeEscaper escapes the LIKE wildcards of a search term, for ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *ProductRepository) ReplaceProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE products SET category_id = $2, name = $3, price = $4, description = $5, image = $6,
+			availability = $7, back_at = $8, created_at = $9, updated_at = $10, search_text = $11
+		WHERE id = $1`,
+		product.ID, product.CategoryId, product.Name, product.Price, product.Description, product.Image,
+		product.Availability.Status, backAt(product.Availability), product.CreatedAt, product.UpdatedAt, product.SearchText())
+	if err != nil {
+		return nil, r.DB.translateError(err, productCategoryMessage)
+	}
+	if err := requireAffected(result); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// UpdateProduct changes only the fields that are set on product.
+func (r *ProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var categoryID, updatedAt any
+	if product.CategoryId != uuid.Nil {
+		categoryID = product.CategoryId
+	}
+	if !product.UpdatedAt.IsZero() {
+		updatedAt = product.UpdatedAt
+	}
+
+	q := conn(ctx, r.DB)
+	result, err := q.ExecContext(ctx,
+		`UPDATE products SET
+			category_id = COALESCE($2, category_id),
+			name = COALESCE(NULLIF($3, ''), name),
+			price = COALESCE(NULLIF(CAST($4 AS numeric), 0), price),
+			description = COALESCE(NULLIF($5, ''), description),
+			image = COALESCE(NULLIF($6, ''), image),
+			updated_at = COALESCE($7, updated_at)
+		WHERE id = $1`,
+		product.ID, categoryID, product.Name, product.Price, product.Description, product.Image, updatedAt)
+	if err != nil {
+		return nil, r.DB.translateError(err, productCategoryMessage)
+	}
+	if err := requireAffected(result); err != nil {
+		return nil, err
+	}
+
+	// The search text depends on both the name and the description, so it is rebuilt
+	// from the updated row.
+	if product.Name != "" || product.Description != "" {
+		updated, err := r.GetProductByID(ctx, product.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if _, err := q.ExecContext(ctx, `UPDATE products SET search_text = $2 WHERE id = $1`, product.ID, updated.SearchText()); err != nil {
+			return nil, err
+		}
+	}
+	return product, nil
+}
+
+func (r *ProductRepository) SetAvailability(ctx context.Context, id uuid.UUID, availability domain.Availability) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE products SET availability = $2, back_at = $3, updated_at = $4 WHERE id = $1`,
+		id, availability.Status, backAt(availability), time.Now())
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// backAt returns the back_at column value of availability, in server time.
+func backAt(availability domain.Availability) any {
+	if availability.BackAt == nil {
+		return nil
+	}
+	return availability.BackAt.Local()
+}
+
+func (r *ProductRepository) RecordSales(ctx context.Context, items []domain.OrderItem) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	q := conn(ctx, r.DB)
+	for _, item := range items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, `UPDATE products SET sold = sold + $2 WHERE id = $1`, productID, item.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, `DELETE FROM products WHERE id = $1`, uuidID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func scanProduct(row rowScanner) (*domain.Product, error) {
+	var product domain.Product
+	var backAt sql.NullTime
+	err := row.Scan(&product.ID, &product.CategoryId, &product.Name, &product.Price, &product.Description,
+		&product.Image, &product.Availability.Status, &backAt, &product.CreatedAt, &product.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if backAt.Valid {
+		product.Availability.BackAt = &backAt.Time
+	}
+	return &product, nil
+}
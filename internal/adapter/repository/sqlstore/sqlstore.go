@@ -0,0 +1,113 @@
+// Package sqlstore implements the repository ports on SQL databases with database/sql.
+// The statements are shared by every supported database; what differs between them is
+// described by a Dialect, provided by the postgres and sqlite packages.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// Dialect describes what differs between the databases the repositories run on.
+type Dialect struct {
+	// Migrations holds the schema scripts, named <version>_<description>.sql.
+	Migrations fs.FS
+	// ClaimLock is appended to the subqueries that select outbox messages and webhook
+	// deliveries to claim, e.g. FOR UPDATE SKIP LOCKED. Empty when writes are serialized anyway.
+	ClaimLock string
+	// TableExists selects whether the table named by its single parameter exists.
+	TableExists string
+	// IsConflict reports whether err is a unique or foreign key violation.
+	IsConflict func(err error) bool
+	// LockMigrations keeps other processes from migrating until unlock is called.
+	// It may be nil when a single process owns the database.
+	LockMigrations func(ctx context.Context, c *sql.Conn) (unlock func(), err error)
+}
+
+// DB is a connection pool together with the dialect of the database behind it.
+type DB struct {
+	*sql.DB
+	dialect Dialect
+}
+
+func New(db *sql.DB, dialect Dialect) *DB {
+	return &DB{DB: db, dialect: dialect}
+}
+
+// querier is the subset of *sql.DB and *sql.Tx the repositories use.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction started by the UnitOfWork running ctx, or db outside a unit.
+func conn(ctx context.Context, db *DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db.DB
+}
+
+// withTimeout bounds a repository call by the timeout of its repository, unless ctx has an
+// earlier deadline.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, timeout)
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// translateError maps driver errors to the domain errors the services and handlers understand.
+func (db *DB) translateError(err error, conflictMessage string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil && db.dialect.IsConflict(err) {
+		return fmt.Errorf("%w: %s", domain.ErrConflict, conflictMessage)
+	}
+	return err
+}
+
+// requireAffected returns domain.ErrNotFound when a statement matched no row.
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func offset(page, limit int) int {
+	return (page - 1) * limit
+}
+
+// count runs a SELECT COUNT(*) query, for the totals of paginated lists.
+func count(ctx context.Context, q querier, query string, args ...any) (int64, error) {
+	var total int64
+	if err := q.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// placeholders returns "$first, ..., $first+n-1".
+func placeholders(first, n int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = fmt.Sprintf("$%d", first+i)
+	}
+	return strings.Join(params, ", ")
+}
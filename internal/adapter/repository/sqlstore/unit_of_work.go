@@ -0,0 +1,42 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+)
+
+type txKey struct{}
+
+// UnitOfWork runs a function inside a database transaction. Repositories called with
+// the context it passes on join the transaction; nested units reuse the outer one.
+type UnitOfWork struct {
+	db *DB
+}
+
+func NewUnitOfWork(db *DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logging.FromContext(ctx).Error("transaction rollback failed", "error", rollbackErr)
+		} else {
+			logging.FromContext(ctx).Debug("transaction rolled back", "error", err)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,346 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+const (
+	subscriptionColumns = "id, url, secret, created_at, updated_at"
+	deliveryColumns     = "id, subscription_id, event_id, event_type, payload, status, next_attempt_at, attempts, created_at, updated_at"
+)
+
+type WebhookSubscriptionRepository struct {
+	DB      *DB
+	timeout time.Duration
+}
+
+func NewWebhookSubscriptionRepository(db *DB, timeout time.Duration) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{DB: db, timeout: timeout}
+}
+
+func (r *WebhookSubscriptionRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	err := NewUnitOfWork(r.DB).Do(ctx, func(ctx context.Context) error {
+		_, err := conn(ctx, r.DB).ExecContext(ctx,
+			`INSERT INTO webhook_subscriptions (`+subscriptionColumns+`) VALUES ($1, $2, $3, $4, $5)`,
+			subscription.ID, subscription.URL, subscription.Secret, subscription.CreatedAt, subscription.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return r.saveEventTypes(ctx, subscription)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := r.find(ctx, `WHERE id = $1`, uuidID)
+	if err != nil {
+		return nil, err
+	}
+	if len(subscriptions) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &subscriptions[0], nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptions(ctx context.Context, page, limit int) ([]domain.WebhookSubscription, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	total, err := count(ctx, conn(ctx, r.DB), `SELECT COUNT(*) FROM webhook_subscriptions`)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	subscriptions, err := r.find(ctx, `ORDER BY created_at, id LIMIT $1 OFFSET $2`, limit, offset(page, limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	return subscriptions, total, nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptionsByEventType(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.find(ctx,
+		`WHERE id IN (SELECT subscription_id FROM webhook_subscription_event_types WHERE event_type IN ($1, '*'))`,
+		eventType)
+}
+
+func (r *WebhookSubscriptionRepository) ReplaceSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	err := NewUnitOfWork(r.DB).Do(ctx, func(ctx context.Context) error {
+		q := conn(ctx, r.DB)
+
+		result, err := q.ExecContext(ctx,
+			`UPDATE webhook_subscriptions SET url = $2, secret = $3, created_at = $4, updated_at = $5 WHERE id = $1`,
+			subscription.ID, subscription.URL, subscription.Secret, subscription.CreatedAt, subscription.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+
+		if _, err := q.ExecContext(ctx, `DELETE FROM webhook_subscription_event_types WHERE subscription_id = $1`, subscription.ID); err != nil {
+			return err
+		}
+		return r.saveEventTypes(ctx, subscription)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) DeleteSubscription(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, uuidID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *WebhookSubscriptionRepository) saveEventTypes(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	for i, eventType := range subscription.EventTypes {
+		_, err := conn(ctx, r.DB).ExecContext(ctx,
+			`INSERT INTO webhook_subscription_event_types (subscription_id, position, event_type) VALUES ($1, $2, $3)`,
+			subscription.ID, i, eventType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns the subscriptions selected by clause together with their event types.
+func (r *WebhookSubscriptionRepository) find(ctx context.Context, clause string, args ...any) ([]domain.WebhookSubscription, error) {
+	q := conn(ctx, r.DB)
+	rows, err := q.QueryContext(ctx, `SELECT `+subscriptionColumns+` FROM webhook_subscriptions `+clause, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []domain.WebhookSubscription
+	for rows.Next() {
+		var subscription domain.WebhookSubscription
+		err := rows.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, &subscription.CreatedAt, &subscription.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(subscriptions) == 0 {
+		return subscriptions, nil
+	}
+
+	index := make(map[uuid.UUID]int, len(subscriptions))
+	ids := make([]any, len(subscriptions))
+	for i, subscription := range subscriptions {
+		index[subscription.ID] = i
+		ids[i] = subscription.ID
+		subscriptions[i].EventTypes = []string{}
+	}
+
+	eventRows, err := q.QueryContext(ctx,
+		`SELECT subscription_id, event_type FROM webhook_subscription_event_types
+		WHERE subscription_id IN (`+placeholders(1, len(ids))+`) ORDER BY subscription_id, position`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer eventRows.Close()
+
+	for eventRows.Next() {
+		var subscriptionID uuid.UUID
+		var eventType string
+		if err := eventRows.Scan(&subscriptionID, &eventType); err != nil {
+			return nil, err
+		}
+		i := index[subscriptionID]
+		subscriptions[i].EventTypes = append(subscriptions[i].EventTypes, eventType)
+	}
+	return subscriptions, eventRows.Err()
+}
+
+type WebhookDeliveryRepository struct {
+	DB      *DB
+	timeout time.Duration
+}
+
+func NewWebhookDeliveryRepository(db *DB, timeout time.Duration) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{DB: db, timeout: timeout}
+}
+
+func (r *WebhookDeliveryRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	attempts, err := json.Marshal(delivery.Attempts)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.DB).ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (`+deliveryColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO NOTHING`,
+		delivery.ID, delivery.SubscriptionID, delivery.EventID, delivery.EventType, string(delivery.Payload),
+		delivery.Status, delivery.NextAttemptAt, string(attempts), delivery.CreatedAt, delivery.UpdatedAt)
+	return err
+}
+
+func (r *WebhookDeliveryRepository) GetDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, uuidID)
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		return nil, r.DB.translateError(err, "")
+	}
+	return delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) GetDeliveries(ctx context.Context, subscriptionID string, page, limit int) ([]domain.WebhookDelivery, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(subscriptionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := conn(ctx, r.DB)
+	total, err := count(ctx, q, `SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = $1`, uuidID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE subscription_id = $1
+		ORDER BY created_at DESC, id LIMIT $2 OFFSET $3`,
+		uuidID, limit, offset(page, limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// ClaimDue pushes the next attempt of the claimed deliveries past the lease in the same
+// statement that selects them, skipping rows another worker is claiming concurrently
+// where the dialect supports it.
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	now := time.Now()
+	rows, err := conn(ctx, r.DB).QueryContext(ctx,
+		`UPDATE webhook_deliveries SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries WHERE status = $2 AND next_attempt_at <= $3
+			ORDER BY next_attempt_at LIMIT $4 `+r.DB.dialect.ClaimLock+`
+		)
+		RETURNING `+deliveryColumns,
+		now.Add(lease), domain.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) ReplaceDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	attempts, err := json.Marshal(delivery.Attempts)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = $2, next_attempt_at = $3, attempts = $4, updated_at = $5 WHERE id = $1`,
+		delivery.ID, delivery.Status, delivery.NextAttemptAt, string(attempts), delivery.UpdatedAt)
+	return err
+}
+
+func scanDeliveries(rows *sql.Rows) ([]domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var payload, attempts []byte
+	err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &payload,
+		&delivery.Status, &delivery.NextAttemptAt, &attempts, &delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	if err := json.Unmarshal(attempts, &delivery.Attempts); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// UnitOfWork implements port.UnitOfWork with multi-document transactions, which require
+// MongoDB to run as a replica set or sharded cluster. Against a standalone server it runs
+// the work without a transaction; migrations and tests rely on that, but the API refuses
+// to start unless Transactional reports true.
+type UnitOfWork struct {
+	client        *mongo.Client
+	transactional bool
+}
+
+func NewUnitOfWork(ctx context.Context, client *mongo.Client) (*UnitOfWork, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return nil, err
+	}
+
+	return &UnitOfWork{
+		client:        client,
+		transactional: hello.SetName != "" || hello.Msg == "isdbgrid",
+	}, nil
+}
+
+// Transactional reports whether the server supports transactions.
+func (u *UnitOfWork) Transactional() bool {
+	return u.transactional
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !u.transactional || mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := u.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// Transactions must read from the primary whatever read preference the client uses.
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	}, options.Transaction().SetReadPreference(readpref.Primary()))
+	if err != nil {
+		logging.FromContext(ctx).Debug("transaction aborted", "error", err)
+	}
+	return err
+}
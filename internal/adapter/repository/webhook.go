@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookSubscriptionRepository struct {
+	Collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewWebhookSubscriptionRepository(db *mongo.Database, timeout time.Duration) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{Collection: db.Collection("webhook_subscriptions"), timeout: timeout}
+}
+
+func (r *WebhookSubscriptionRepository) EnsureIndexes(ctx context.Context) error {
+	return createIndexes(ctx, r.Collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "event_types", Value: 1}}, Options: options.Index().SetName("event_types")},
+	})
+}
+
+func (r *WebhookSubscriptionRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := r.Collection.InsertOne(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscription domain.WebhookSubscription
+	if err := r.Collection.FindOne(ctx, bson.M{"_id": uuidID}).Decode(&subscription); err != nil {
+		return nil, translateError(err, "")
+	}
+	return &subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptions(ctx context.Context, page, limit int) ([]domain.WebhookSubscription, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	total, err := r.Collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetSkip(int64((page - 1) * limit)).SetLimit(int64(limit))
+	subscriptions, err := r.find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return subscriptions, total, nil
+}
+
+func (r *WebhookSubscriptionRepository) GetSubscriptionsByEventType(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.find(ctx, bson.M{"event_types": bson.M{"$in": bson.A{eventType, "*"}}})
+}
+
+func (r *WebhookSubscriptionRepository) ReplaceSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := r.Collection.ReplaceOne(ctx, bson.M{"_id": subscription.ID}, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) DeleteSubscription(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.Collection.DeleteOne(ctx, bson.M{"_id": uuidID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cursor, err := r.Collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscriptions []domain.WebhookSubscription
+	if err := cursor.All(ctx, &subscriptions); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+type WebhookDeliveryRepository struct {
+	Collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewWebhookDeliveryRepository(db *mongo.Database, timeout time.Duration) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{Collection: db.Collection("webhook_deliveries"), timeout: timeout}
+}
+
+func (r *WebhookDeliveryRepository) EnsureIndexes(ctx context.Context) error {
+	return createIndexes(ctx, r.Collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}, Options: options.Index().SetName("status_next_attempt_at")},
+		{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("subscription_id_created_at")},
+	})
+}
+
+func (r *WebhookDeliveryRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.Collection.InsertOne(ctx, delivery)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *WebhookDeliveryRepository) GetDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivery domain.WebhookDelivery
+	if err := r.Collection.FindOne(ctx, bson.M{"_id": uuidID}).Decode(&delivery); err != nil {
+		return nil, translateError(err, "")
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) GetDeliveries(ctx context.Context, subscriptionID string, page, limit int) ([]domain.WebhookDelivery, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	uuidID, err := uuid.Parse(subscriptionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter := bson.M{"subscription_id": uuidID}
+	total, err := r.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(int64((page - 1) * limit)).SetLimit(int64(limit))
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []domain.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var deliveries []domain.WebhookDelivery
+
+	for len(deliveries) < limit {
+		now := time.Now()
+		filter := bson.M{"status": domain.WebhookDeliveryPending, "next_attempt_at": bson.M{"$lte": now}}
+		update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+		opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}})
+
+		var delivery domain.WebhookDelivery
+		err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) ReplaceDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.Collection.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery)
+	return err
+}
@@ -0,0 +1,231 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+// The functions below wrap a service port so each call gets a span. Services that call
+// each other through their ports, like OrderService looking up clients and products,
+// should be given the wrapped ports so those calls nest under the caller's span.
+
+type categoryService struct {
+	next port.CategoryService
+	tracer
+}
+
+func CategoryService(next port.CategoryService) port.CategoryService {
+	return categoryService{next: next, tracer: tracer{service: "CategoryService"}}
+}
+
+func (s categoryService) CreateCategory(ctx context.Context, category port.CreateCategoryRequest) (_ *domain.Category, err error) {
+	ctx, span := s.start(ctx, "CreateCategory")
+	defer end(span, &err)
+	return s.next.CreateCategory(ctx, category)
+}
+
+func (s categoryService) GetCategoryByID(ctx context.Context, id string) (_ *domain.Category, err error) {
+	ctx, span := s.start(ctx, "GetCategoryByID")
+	defer end(span, &err)
+	return s.next.GetCategoryByID(ctx, id)
+}
+
+func (s categoryService) GetCategories(ctx context.Context, page, size int) (_ []domain.Category, _ int64, err error) {
+	ctx, span := s.start(ctx, "GetCategories")
+	defer end(span, &err)
+	return s.next.GetCategories(ctx, page, size)
+}
+
+func (s categoryService) ReplaceCategory(ctx context.Context, id string, category *domain.Category) (_ *domain.Category, err error) {
+	ctx, span := s.start(ctx, "ReplaceCategory")
+	defer end(span, &err)
+	return s.next.ReplaceCategory(ctx, id, category)
+}
+
+func (s categoryService) UpdateCategory(ctx context.Context, id string, category *domain.Category) (_ *domain.Category, err error) {
+	ctx, span := s.start(ctx, "UpdateCategory")
+	defer end(span, &err)
+	return s.next.UpdateCategory(ctx, id, category)
+}
+
+func (s categoryService) DeleteCategory(ctx context.Context, id string) (err error) {
+	ctx, span := s.start(ctx, "DeleteCategory")
+	defer end(span, &err)
+	return s.next.DeleteCategory(ctx, id)
+}
+
+type productService struct {
+	next port.ProductService
+	tracer
+}
+
+func ProductService(next port.ProductService) port.ProductService {
+	return productService{next: next, tracer: tracer{service: "ProductService"}}
+}
+
+func (s productService) CreateProduct(ctx context.Context, product port.CreateProductRequest) (_ *domain.Product, err error) {
+	ctx, span := s.start(ctx, "CreateProduct")
+	defer end(span, &err)
+	return s.next.CreateProduct(ctx, product)
+}
+
+func (s productService) GetProductByID(ctx context.Context, id string) (_ *domain.Product, err error) {
+	ctx, span := s.start(ctx, "GetProductByID")
+	defer end(span, &err)
+	return s.next.GetProductByID(ctx, id)
+}
+
+func (s productService) GetProducts(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, page, size int) (_ []domain.Product, _ int64, err error) {
+	ctx, span := s.start(ctx, "GetProducts")
+	defer end(span, &err)
+	return s.next.GetProducts(ctx, filter, sort, page, size)
+}
+
+func (s productService) ReplaceProduct(ctx context.Context, id string, product port.CreateProductRequest) (_ *domain.Product, err error) {
+	ctx, span := s.start(ctx, "ReplaceProduct")
+	defer end(span, &err)
+	return s.next.ReplaceProduct(ctx, id, product)
+}
+
+func (s productService) UpdateProduct(ctx context.Context, id string, product port.CreateProductRequest) (_ *domain.Product, err error) {
+	ctx, span := s.start(ctx, "UpdateProduct")
+	defer end(span, &err)
+	return s.next.UpdateProduct(ctx, id, product)
+}
+
+func (s productService) DeleteProduct(ctx context.Context, id string) (err error) {
+	ctx, span := s.start(ctx, "DeleteProduct")
+	defer end(span, &err)
+	return s.next.DeleteProduct(ctx, id)
+}
+
+func (s productService) SetAvailability(ctx context.Context, id string, availability port.SetAvailabilityRequest) (_ *domain.Product, err error) {
+	ctx, span := s.start(ctx, "SetAvailability")
+	defer end(span, &err)
+	return s.next.SetAvailability(ctx, id, availability)
+}
+
+func (s productService) RecordSales(ctx context.Context, items []domain.OrderItem) (err error) {
+	ctx, span := s.start(ctx, "RecordSales")
+	defer end(span, &err)
+	return s.next.RecordSales(ctx, items)
+}
+
+type clientService struct {
+	next port.ClientService
+	tracer
+}
+
+func ClientService(next port.ClientService) port.ClientService {
+	return clientService{next: next, tracer: tracer{service: "ClientService"}}
+}
+
+func (s clientService) CreateClient(ctx context.Context, client port.CreateClientRequest) (_ *domain.Client, err error) {
+	ctx, span := s.start(ctx, "CreateClient")
+	defer end(span, &err)
+	return s.next.CreateClient(ctx, client)
+}
+
+func (s clientService) GetClientByCPF(ctx context.Context, cpf string) (_ *domain.Client, err error) {
+	ctx, span := s.start(ctx, "GetClientByCPF")
+	defer end(span, &err)
+	return s.next.GetClientByCPF(ctx, cpf)
+}
+
+type orderService struct {
+	next port.OrderService
+	tracer
+}
+
+func OrderService(next port.OrderService) port.OrderService {
+	return orderService{next: next, tracer: tracer{service: "OrderService"}}
+}
+
+func (s orderService) CreateOrder(ctx context.Context, order port.CreateOrderRequest) (_ *domain.Order, err error) {
+	ctx, span := s.start(ctx, "CreateOrder")
+	defer end(span, &err)
+	return s.next.CreateOrder(ctx, order)
+}
+
+func (s orderService) GetOrderByID(ctx context.Context, id string) (_ *domain.Order, err error) {
+	ctx, span := s.start(ctx, "GetOrderByID")
+	defer end(span, &err)
+	return s.next.GetOrderByID(ctx, id)
+}
+
+func (s orderService) GetOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, page, size int) (_ []domain.Order, _ int64, err error) {
+	ctx, span := s.start(ctx, "GetOrders")
+	defer end(span, &err)
+	return s.next.GetOrders(ctx, filter, sort, page, size)
+}
+
+func (s orderService) GetOrdersByCursor(ctx context.Context, filter domain.OrderFilter, after, before string, size int) (_ *domain.OrderCursorPage, err error) {
+	ctx, span := s.start(ctx, "GetOrdersByCursor")
+	defer end(span, &err)
+	return s.next.GetOrdersByCursor(ctx, filter, after, before, size)
+}
+
+func (s orderService) SetOrderStatus(ctx context.Context, id string, status int) (_ *domain.OrderStatus, err error) {
+	ctx, span := s.start(ctx, "SetOrderStatus")
+	defer end(span, &err)
+	return s.next.SetOrderStatus(ctx, id, status)
+}
+
+type webhookService struct {
+	next port.WebhookService
+	tracer
+}
+
+func WebhookService(next port.WebhookService) port.WebhookService {
+	return webhookService{next: next, tracer: tracer{service: "WebhookService"}}
+}
+
+func (s webhookService) CreateSubscription(ctx context.Context, subscription port.CreateWebhookSubscriptionRequest) (_ *domain.WebhookSubscription, err error) {
+	ctx, span := s.start(ctx, "CreateSubscription")
+	defer end(span, &err)
+	return s.next.CreateSubscription(ctx, subscription)
+}
+
+func (s webhookService) ReplaceSubscription(ctx context.Context, id string, subscription port.CreateWebhookSubscriptionRequest) (_ *domain.WebhookSubscription, err error) {
+	ctx, span := s.start(ctx, "ReplaceSubscription")
+	defer end(span, &err)
+	return s.next.ReplaceSubscription(ctx, id, subscription)
+}
+
+func (s webhookService) GetSubscriptionByID(ctx context.Context, id string) (_ *domain.WebhookSubscription, err error) {
+	ctx, span := s.start(ctx, "GetSubscriptionByID")
+	defer end(span, &err)
+	return s.next.GetSubscriptionByID(ctx, id)
+}
+
+func (s webhookService) GetSubscriptions(ctx context.Context, page, size int) (_ []domain.WebhookSubscription, _ int64, err error) {
+	ctx, span := s.start(ctx, "GetSubscriptions")
+	defer end(span, &err)
+	return s.next.GetSubscriptions(ctx, page, size)
+}
+
+func (s webhookService) DeleteSubscription(ctx context.Context, id string) (err error) {
+	ctx, span := s.start(ctx, "DeleteSubscription")
+	defer end(span, &err)
+	return s.next.DeleteSubscription(ctx, id)
+}
+
+func (s webhookService) GetDeliveries(ctx context.Context, subscriptionID string, page, size int) (_ []domain.WebhookDelivery, _ int64, err error) {
+	ctx, span := s.start(ctx, "GetDeliveries")
+	defer end(span, &err)
+	return s.next.GetDeliveries(ctx, subscriptionID, page, size)
+}
+
+func (s webhookService) GetDeliveryByID(ctx context.Context, id string) (_ *domain.WebhookDelivery, err error) {
+	ctx, span := s.start(ctx, "GetDeliveryByID")
+	defer end(span, &err)
+	return s.next.GetDeliveryByID(ctx, id)
+}
+
+func (s webhookService) Redeliver(ctx context.Context, id string) (_ *domain.WebhookDelivery, err error) {
+	ctx, span := s.start(ctx, "Redeliver")
+	defer end(span, &err)
+	return s.next.Redeliver(ctx, id)
+}
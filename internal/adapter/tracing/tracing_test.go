@@ -0,0 +1,74 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/tracing"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeCategories fails every lookup; the other methods are not used.
+type fakeCategories struct {
+	port.CategoryService
+}
+
+func (fakeCategories) GetCategoryByID(ctx context.Context, id string) (*domain.Category, error) {
+	return nil, errors.New("category not found")
+}
+
+func TestSpansContinueTheIncomingTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	categories := tracing.CategoryService(fakeCategories{})
+	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
+	r.Get("/categories/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := categories.GetCategoryByID(r.Context(), chi.URLParam(r, "id")); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/categories/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	serviceSpan, requestSpan := spans[0], spans[1]
+
+	if got := requestSpan.Name(); got != "GET /categories/{id}" {
+		t.Errorf("request span named %q, want it named after the route", got)
+	}
+	if got := requestSpan.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("request span in trace %s, want the incoming trace %s", got, traceID)
+	}
+	if got := requestSpan.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("request span parent %s, want the caller's span", got)
+	}
+
+	if got := serviceSpan.Name(); got != "CategoryService.GetCategoryByID" {
+		t.Errorf("service span named %q", got)
+	}
+	if serviceSpan.Parent().SpanID() != requestSpan.SpanContext().SpanID() {
+		t.Error("service span is not a child of the request span")
+	}
+	if serviceSpan.Status().Code != codes.Error || len(serviceSpan.Events()) == 0 {
+		t.Errorf("service span status %v with %d events, want the error recorded", serviceSpan.Status().Code, len(serviceSpan.Events()))
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,5 +27,18 @@ func NewCategory(name string, description string) (*Category, error) {
 		UpdatedAt:   now,
 	}
 
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+
 	return category, nil
 }
+
+// Validate reports whether the category satisfies the domain invariants.
+func (c *Category) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return validationError("category name must not be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewCategory(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		category string
+		wantErr  bool
+	}{
+		{"Valid", "Lanche", false},
+		{"EmptyName", "", true},
+		{"BlankName", " \t", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			category, err := NewCategory(tc.category, "Hamburgers")
+			if tc.wantErr {
+				if !errors.Is(err, ErrValidation) || category != nil {
+					t.Fatalf("NewCategory = %v, %v, want ErrValidation", category, err)
+				}
+				return
+			}
+			if err != nil || category.Name != tc.category {
+				t.Fatalf("NewCategory = %v, %v, want %q", category, err, tc.category)
+			}
+		})
+	}
+}
@@ -1,24 +1,39 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
 type Client struct {
 	Name      string    `json:"name" bson:"name"`
-	Cpf       string    `json:"cpf" bson:"cpf"`
-	Mail      string    `json:"mail" bson:"mail"`
+	Cpf       CPF       `json:"cpf" bson:"cpf"`
+	Mail      Email     `json:"mail" bson:"mail"`
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
 func NewClient(name string, cpf string, mail string) (*Client, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, validationError("client name must not be empty")
+	}
+
+	clientCpf, err := NewCPF(cpf)
+	if err != nil {
+		return nil, err
+	}
+
+	clientMail, err := NewEmail(mail)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 
 	client := &Client{
 		Name:      name,
-		Cpf:       cpf,
-		Mail:      mail,
+		Cpf:       clientCpf,
+		Mail:      clientMail,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		client    string
+		cpf, mail string
+		wantErr   bool
+		wantCpf   CPF
+		wantMail  Email
+	}{
+		{"Valid", "Ana", "529.982.247-25", " Ana@Example.com", false, "52998224725", "ana@example.com"},
+		{"EmptyName", "", "52998224725", "ana@example.com", true, "", ""},
+		{"BlankName", "  ", "52998224725", "ana@example.com", true, "", ""},
+		{"InvalidCPF", "Ana", "11111111111", "ana@example.com", true, "", ""},
+		{"InvalidEmail", "Ana", "52998224725", "ana@example", true, "", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := NewClient(tc.client, tc.cpf, tc.mail)
+			if tc.wantErr {
+				if !errors.Is(err, ErrValidation) || client != nil {
+					t.Fatalf("NewClient = %v, %v, want ErrValidation", client, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if client.Cpf != tc.wantCpf || client.Mail != tc.wantMail {
+				t.Errorf("NewClient = %s, %s, want %s, %s", string(client.Cpf), string(client.Mail), string(tc.wantCpf), string(tc.wantMail))
+			}
+		})
+	}
+}
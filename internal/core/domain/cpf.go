@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+var nonDigits = regexp.MustCompile(`[^0-9]+`)
+
+// CPF is a Brazilian taxpayer registry number normalized to its eleven digits.
+// String, LogValue and MarshalJSON render it masked so it does not leak into logs,
+// API responses, events or webhook payloads; convert it with string(cpf) when the
+// full number is needed.
+type CPF string
+
+// NewCPF strips punctuation from value and validates the resulting digits.
+func NewCPF(value string) (CPF, error) {
+	digits := nonDigits.ReplaceAllString(value, "")
+	if !isValidCPF(digits) {
+		return "", validationError("invalid CPF format")
+	}
+
+	return CPF(digits), nil
+}
+
+// Masked hides all but the middle six digits, e.g. ***.456.789-**.
+func (c CPF) Masked() string {
+	if len(c) != 11 {
+		return strings.Repeat("*", len(c))
+	}
+
+	return "***." + string(c[3:6]) + "." + string(c[6:9]) + "-**"
+}
+
+func (c CPF) String() string {
+	return c.Masked()
+}
+
+func (c CPF) LogValue() slog.Value {
+	return slog.StringValue(c.Masked())
+}
+
+func (c CPF) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Masked())
+}
+
+func isValidCPF(cpf string) bool {
+	if len(cpf) != 11 {
+		return false
+	}
+
+	// Sequences of a single repeated digit pass the check digit algorithm but are never issued.
+	if strings.Count(cpf, cpf[:1]) == len(cpf) {
+		return false
+	}
+
+	var sum int
+	var remainder int
+
+	for i := 1; i <= 9; i++ {
+		sum += int(cpf[i-1]-'0') * (11 - i)
+	}
+	remainder = (sum * 10) % 11
+
+	if remainder == 10 || remainder == 11 {
+		remainder = 0
+	}
+	if remainder != int(cpf[9]-'0') {
+		return false
+	}
+
+	sum = 0
+	for i := 1; i <= 10; i++ {
+		sum += int(cpf[i-1]-'0') * (12 - i)
+	}
+	remainder = (sum * 10) % 11
+
+	if remainder == 10 || remainder == 11 {
+		remainder = 0
+	}
+	if remainder != int(cpf[10]-'0') {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewCPF(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		want  CPF
+	}{
+		{"52998224725", "52998224725"},
+		{"529.982.247-25", "52998224725"},
+		{" 529 982 247 25 ", "52998224725"},
+		{"529/982.247_25", "52998224725"},
+	} {
+		got, err := NewCPF(tc.value)
+		if err != nil || got != tc.want {
+			t.Errorf("NewCPF(%q) = %q, %v, want %q", tc.value, string(got), err, string(tc.want))
+		}
+	}
+}
+
+func TestNewCPFRejectsInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"5299822472",   // too short
+		"529982247250", // too long
+		"52998224724",  // wrong check digit
+		"00000000000",
+		"111.111.111-11",
+		"99999999999",
+	} {
+		if _, err := NewCPF(value); !errors.Is(err, ErrValidation) {
+			t.Errorf("NewCPF(%q) error = %v, want ErrValidation", value, err)
+		}
+	}
+}
+
+func TestCPFIsMasked(t *testing.T) {
+	cpf, err := NewCPF("529.982.247-25")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = "***.982.247-**"
+	if got := cpf.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%v", cpf); got != want {
+		t.Errorf("%%v = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("client", "cpf", cpf)
+	if strings.Contains(buf.String(), string(cpf)) || !strings.Contains(buf.String(), want) {
+		t.Errorf("log line %q does not mask the CPF", buf.String())
+	}
+}
+
+func TestCPFIsMaskedInJSON(t *testing.T) {
+	cpf, err := NewCPF("529.982.247-25")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clients, orders and the OrderCreated event are all written as JSON by the API.
+	for name, value := range map[string]any{
+		"Client":       Client{Name: "Ana", Cpf: cpf},
+		"Order":        Order{Client: cpf},
+		"OrderCreated": OrderCreated{Client: cpf},
+	} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), string(cpf)) || !strings.Contains(string(data), `"***.982.247-**"`) {
+			t.Errorf("%s JSON %s does not mask the CPF", name, data)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+var emailPattern = regexp.MustCompile(`^[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}$`)
+
+// Email is an e-mail address normalized to lower case. String and LogValue mask it
+// like CPF's; string(email) gives the full address.
+type Email string
+
+// NewEmail trims and lower-cases value before validating its format.
+func NewEmail(value string) (Email, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if !emailPattern.MatchString(normalized) {
+		return "", validationError("invalid email format")
+	}
+
+	return Email(normalized), nil
+}
+
+// Masked keeps the first character of the local part and the domain, e.g. j***@example.com.
+func (e Email) Masked() string {
+	at := strings.LastIndex(string(e), "@")
+	if at < 1 {
+		return strings.Repeat("*", len(e))
+	}
+
+	return string(e[:1]) + "***" + string(e[at:])
+}
+
+func (e Email) String() string {
+	return e.Masked()
+}
+
+func (e Email) LogValue() slog.Value {
+	return slog.StringValue(e.Masked())
+}
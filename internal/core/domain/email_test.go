@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewEmail(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		want  Email
+	}{
+		{"ana@example.com", "ana@example.com"},
+		{"  Ana.Souza@Example.COM ", "ana.souza@example.com"},
+		{"ana+pedidos@lanchonete.com.br", "ana+pedidos@lanchonete.com.br"},
+	} {
+		got, err := NewEmail(tc.value)
+		if err != nil || got != tc.want {
+			t.Errorf("NewEmail(%q) = %q, %v, want %q", tc.value, string(got), err, string(tc.want))
+		}
+	}
+}
+
+func TestNewEmailRejectsInvalid(t *testing.T) {
+	for _, value := range []string{"", "ana", "ana@", "@example.com", "ana@example", "ana souza@example.com"} {
+		if _, err := NewEmail(value); !errors.Is(err, ErrValidation) {
+			t.Errorf("NewEmail(%q) error = %v, want ErrValidation", value, err)
+		}
+	}
+}
+
+func TestEmailIsMasked(t *testing.T) {
+	email, err := NewEmail("ana@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = "a***@example.com"
+	if got := email.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("client", "mail", email)
+	if strings.Contains(buf.String(), string(email)) || !strings.Contains(buf.String(), want) {
+		t.Errorf("log line %q does not mask the e-mail", buf.String())
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrValidation is wrapped by every error returned when a domain invariant is violated.
+	ErrValidation = errors.New("validation failed")
+	// ErrConflict is wrapped by errors returned when an entity would duplicate a unique attribute of another one.
+	ErrConflict = errors.New("conflict")
+	// ErrNotFound is returned by repositories when no entity matches the given identifier.
+	ErrNotFound = errors.New("not found")
+)
+
+func validationError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrValidation, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventOrderCreated        = "OrderCreated"
+	EventOrderStatusChanged  = "OrderStatusChanged"
+	EventProductPriceChanged = "ProductPriceChanged"
+)
+
+// Event records something that happened to an aggregate and that other systems may react to.
+type Event struct {
+	ID          uuid.UUID       `json:"id" bson:"_id"`
+	Type        string          `json:"type" bson:"type"`
+	AggregateID uuid.UUID       `json:"aggregate_id" bson:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload" bson:"payload" swaggertype:"object"`
+	OccurredAt  time.Time       `json:"occurred_at" bson:"occurred_at"`
+}
+
+// OutboxMessage is an event waiting in the outbox to be delivered to the event publisher.
+type OutboxMessage struct {
+	Event         `bson:",inline"`
+	Attempts      int        `json:"attempts" bson:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" bson:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at" bson:"delivered_at"`
+	LastError     string     `json:"last_error" bson:"last_error"`
+}
+
+type OrderCreated struct {
+	OrderID uuid.UUID   `json:"order_id"`
+	Client  CPF         `json:"client"`
+	Items   []OrderItem `json:"items"`
+	Total   float64     `json:"total"`
+}
+
+type OrderStatusChanged struct {
+	OrderID           uuid.UUID `json:"order_id"`
+	PreviousStatus    int       `json:"previous_status"`
+	Status            int       `json:"status"`
+	StatusDescription string    `json:"status_description"`
+}
+
+type ProductPriceChanged struct {
+	ProductID uuid.UUID `json:"product_id"`
+	OldPrice  float64   `json:"old_price"`
+	NewPrice  float64   `json:"new_price"`
+}
+
+func NewEvent(eventType string, aggregateID uuid.UUID, payload any) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		ID:          uuid.New(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+		OccurredAt:  time.Now(),
+	}
+
+	return event, nil
+}
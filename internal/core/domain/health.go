@@ -0,0 +1,21 @@
+package domain
+
+const (
+	HealthUp       = "up"
+	HealthDown     = "down"
+	HealthDraining = "draining"
+)
+
+// DependencyHealth is the outcome of checking one dependency of the API.
+type DependencyHealth struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReport tells whether the API is ready to take traffic. Status is up only
+// when every check is up and the API is not shutting down.
+type HealthReport struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyHealth `json:"checks"`
+}
@@ -1,15 +1,25 @@
 package domain
 
 import (
-	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Order statuses, in the order an order moves through them. Orders are created
+// before checkout and become received once paid.
+const (
+	OrderStatusCreated   = 0
+	OrderStatusReceived  = 1
+	OrderStatusPreparing = 2
+	OrderStatusReady     = 3
+	OrderStatusFinished  = 4
+)
+
 type Order struct {
 	ID                uuid.UUID   `bson:"_id" json:"id"`
-	Client            string      `bson:"client" json:"client"`
+	Client            CPF         `bson:"client" json:"client"`
 	Items             []OrderItem `bson:"items" json:"items"`
 	Total             float64     `bson:"total" json:"total"`
 	Status            int         `bson:"status" json:"status"`
@@ -30,12 +40,36 @@ type OrderStatus struct {
 	StatusDescription string ` json:"status_description"`
 }
 
+// totalTolerance absorbs floating point drift when comparing the order total with its items.
+const totalTolerance = 0.005
+
 func NewOrder(client string, items []OrderItem, status int, total float64, statusDescription string) (*Order, error) {
+	clientCpf, err := NewCPF(client)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, validationError("order must have at least one item")
+	}
+
+	var itemsTotal float64
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return nil, err
+		}
+		itemsTotal += item.Price
+	}
+
+	if math.Abs(itemsTotal-total) > totalTolerance {
+		return nil, validationError("order total %.2f does not match the sum of its items %.2f", total, itemsTotal)
+	}
+
 	now := time.Now()
 
 	order := &Order{
 		ID:                uuid.New(),
-		Client:            client,
+		Client:            clientCpf,
 		Items:             items,
 		Total:             total,
 		Status:            status,
@@ -47,19 +81,35 @@ func NewOrder(client string, items []OrderItem, status int, total float64, statu
 	return order, nil
 }
 
+// Validate reports whether the order item satisfies the domain invariants.
+// Price holds the line total, that is, the unit price multiplied by the quantity.
+func (i OrderItem) Validate() error {
+	if i.ProductID == "" {
+		return validationError("order item must reference a product")
+	}
+	if i.Quantity <= 0 {
+		return validationError("quantity of product %s must be greater than zero", i.ProductID)
+	}
+	if i.Price < 0 {
+		return validationError("price of product %s must not be negative", i.ProductID)
+	}
+
+	return nil
+}
+
 func SetStatus(status int) (*OrderStatus, error) {
 	var statusDescription string
 
-	if status == 1 {
+	if status == OrderStatusReceived {
 		statusDescription = "received"
-	} else if status == 2 {
+	} else if status == OrderStatusPreparing {
 		statusDescription = "preparing"
-	} else if status == 3 {
+	} else if status == OrderStatusReady {
 		statusDescription = "ready"
-	} else if status == 4 {
+	} else if status == OrderStatusFinished {
 		statusDescription = "finished"
 	} else {
-		return nil, fmt.Errorf("invalid status")
+		return nil, validationError("invalid status")
 	}
 
 	OrderStatus := &OrderStatus{
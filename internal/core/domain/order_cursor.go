@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderCursor is the position of an order in cursor-paginated lists, which are sorted
+// newest first by creation time and then by ID, so orders created at the same instant
+// still have a stable order.
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func NewOrderCursor(order Order) OrderCursor {
+	return OrderCursor{CreatedAt: order.CreatedAt, ID: order.ID}
+}
+
+// ParseOrderCursor decodes a token returned by OrderCursor.String.
+func ParseOrderCursor(token string) (OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return OrderCursor{}, validationError("invalid cursor")
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return OrderCursor{}, validationError("invalid cursor")
+	}
+
+	var cursor OrderCursor
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return OrderCursor{}, validationError("invalid cursor")
+	}
+	if cursor.ID, err = uuid.Parse(id); err != nil {
+		return OrderCursor{}, validationError("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// String returns the cursor as an opaque URL-safe token. The time keeps its offset, so
+// stores comparing timestamps as text see the same value they returned.
+func (c OrderCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.Format(time.RFC3339Nano) + "|" + c.ID.String()))
+}
+
+// Precedes reports whether the order at c is listed before the one at other, i.e. is newer.
+func (c OrderCursor) Precedes(other OrderCursor) bool {
+	if !c.CreatedAt.Equal(other.CreatedAt) {
+		return c.CreatedAt.After(other.CreatedAt)
+	}
+	return bytes.Compare(c.ID[:], other.ID[:]) > 0
+}
+
+// OrderCursorQuery selects one page of a cursor-paginated order list. At most one of
+// After and Before is set; without either the page starts at the newest order.
+type OrderCursorQuery struct {
+	Filter OrderFilter
+	// After selects the orders listed after the cursor, i.e. older ones.
+	After *OrderCursor
+	// Before selects the orders listed right before the cursor, i.e. newer ones.
+	Before *OrderCursor
+	Limit  int
+}
+
+// OrderCursorPage is one page of a cursor-paginated order list. Next is set when older
+// orders follow; Prev is set whenever the page has orders, so clients can poll it for
+// orders created since.
+type OrderCursorPage struct {
+	Orders []Order
+	Next   *OrderCursor
+	Prev   *OrderCursor
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// OrderFilter narrows an order list. Zero fields do not filter.
+type OrderFilter struct {
+	// Statuses keeps the orders in any of the given statuses.
+	Statuses []int
+	// CreatedFrom is inclusive and CreatedTo exclusive.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Client      CPF
+	MinTotal    *float64
+	MaxTotal    *float64
+	// ProductID keeps the orders with an item of the product.
+	ProductID string
+}
+
+// Validate reports filters that cannot match any order because of a typo in the request.
+func (f OrderFilter) Validate() error {
+	for _, status := range f.Statuses {
+		if status < OrderStatusCreated || status > OrderStatusFinished {
+			return validationError("invalid status %d", status)
+		}
+	}
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && !f.CreatedFrom.Before(f.CreatedTo) {
+		return validationError("the creation date range is empty")
+	}
+	if f.MinTotal != nil && f.MaxTotal != nil && *f.MinTotal > *f.MaxTotal {
+		return validationError("the minimum total must not exceed the maximum total")
+	}
+	return nil
+}
+
+// Matches reports whether the order passes the filter.
+func (f OrderFilter) Matches(order Order) bool {
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, order.Status) {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && order.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && !order.CreatedAt.Before(f.CreatedTo) {
+		return false
+	}
+	if f.Client != "" && order.Client != f.Client {
+		return false
+	}
+	if f.MinTotal != nil && order.Total < *f.MinTotal {
+		return false
+	}
+	if f.MaxTotal != nil && order.Total > *f.MaxTotal {
+		return false
+	}
+	if f.ProductID != "" {
+		for _, item := range order.Items {
+			if item.ProductID == f.ProductID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Fields page-paginated order lists can be sorted by.
+const (
+	OrderSortCreatedAt = "created_at"
+	OrderSortTotal     = "total"
+	OrderSortStatus    = "status"
+)
+
+// OrderSort orders a page-paginated order list by one field, with the ID breaking ties
+// so pages do not overlap.
+type OrderSort struct {
+	Field      string
+	Descending bool
+}
+
+// ParseOrderSort parses a field name, prefixed with - for descending order. An empty
+// value sorts by creation time, oldest first.
+func ParseOrderSort(value string) (OrderSort, error) {
+	sort := OrderSort{Field: OrderSortCreatedAt}
+	if value == "" {
+		return sort, nil
+	}
+
+	sort.Field, sort.Descending = strings.CutPrefix(value, "-")
+	switch sort.Field {
+	case OrderSortCreatedAt, OrderSortTotal, OrderSortStatus:
+		return sort, nil
+	}
+	return OrderSort{}, validationError("orders cannot be sorted by %q, use created_at, total or status, prefixed with - for descending order", sort.Field)
+}
+
+// Less reports whether a is listed before b.
+func (s OrderSort) Less(a, b Order) bool {
+	var c int
+	switch s.Field {
+	case OrderSortTotal:
+		c = cmp.Compare(a.Total, b.Total)
+	case OrderSortStatus:
+		c = cmp.Compare(a.Status, b.Status)
+	default:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = bytes.Compare(a.ID[:], b.ID[:])
+	}
+	if s.Descending {
+		return c > 0
+	}
+	return c < 0
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewOrder(t *testing.T) {
+	burger := OrderItem{ProductID: "burger", ProductName: "X-Burger", Quantity: 2, Price: 51}
+	soda := OrderItem{ProductID: "soda", ProductName: "Refrigerante", Quantity: 1, Price: 7}
+
+	for _, tc := range []struct {
+		name    string
+		client  string
+		items   []OrderItem
+		total   float64
+		wantErr bool
+	}{
+		{"Valid", "529.982.247-25", []OrderItem{burger, soda}, 58, false},
+		{"TotalWithinTolerance", "52998224725", []OrderItem{burger, soda}, 58.004, false},
+		{"FloatingPointDrift", "52998224725", []OrderItem{{ProductID: "a", Quantity: 1, Price: 0.1}, {ProductID: "b", Quantity: 1, Price: 0.2}}, 0.3, false},
+		{"TotalMismatch", "52998224725", []OrderItem{burger, soda}, 58.01, true},
+		{"TotalBelowItems", "52998224725", []OrderItem{burger, soda}, 51, true},
+		{"NoItems", "52998224725", nil, 0, true},
+		{"ItemWithoutProduct", "52998224725", []OrderItem{{Quantity: 1, Price: 7}}, 7, true},
+		{"ZeroQuantity", "52998224725", []OrderItem{{ProductID: "soda", Quantity: 0, Price: 0}}, 0, true},
+		{"NegativePrice", "52998224725", []OrderItem{{ProductID: "soda", Quantity: 1, Price: -7}}, -7, true},
+		{"InvalidClient", "12345678900", []OrderItem{burger}, 51, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			order, err := NewOrder(tc.client, tc.items, OrderStatusCreated, tc.total, "created")
+			if tc.wantErr {
+				if !errors.Is(err, ErrValidation) || order != nil {
+					t.Fatalf("NewOrder = %v, %v, want ErrValidation", order, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if order.Client != "52998224725" || order.Total != tc.total || len(order.Items) != len(tc.items) {
+				t.Errorf("NewOrder = %+v", order)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+const (
+	DefaultPageSize = 10
+	// MaxPageSize caps the page size of every list, so a single request cannot load a whole collection.
+	MaxPageSize = 100
+)
+
+// NormalizePage defaults a missing page to the first one and a missing page size to
+// DefaultPageSize, and caps the page size at MaxPageSize.
+func NormalizePage(page, size int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	return page, size
+}
@@ -1,35 +1,57 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Product struct {
-	ID          uuid.UUID `json:"id" bson:"_id"`
-	CategoryId  uuid.UUID `json:"category_id" bson:"category_id"`
-	Name        string    `json:"name" bson:"name"`
-	Price       float64   `json:"price" bson:"price"`
-	Description string    `json:"description" bson:"description"`
-	Image       string    `json:"image" bson:"image"`
-	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	ID           uuid.UUID    `json:"id" bson:"_id"`
+	CategoryId   uuid.UUID    `json:"category_id" bson:"category_id"`
+	Name         string       `json:"name" bson:"name"`
+	Price        float64      `json:"price" bson:"price"`
+	Description  string       `json:"description" bson:"description"`
+	Image        string       `json:"image" bson:"image"`
+	Availability Availability `json:"availability" bson:"availability"`
+	CreatedAt    time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" bson:"updated_at"`
 }
 
 func NewProduct(name string, price float64, categoryId uuid.UUID, description string, image string) (*Product, error) {
 	now := time.Now()
 
 	product := &Product{
-		ID:          uuid.New(),
-		CategoryId:  categoryId,
-		Name:        name,
-		Price:       price,
-		Description: description,
-		Image:       image,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:           uuid.New(),
+		CategoryId:   categoryId,
+		Name:         name,
+		Price:        price,
+		Description:  description,
+		Image:        image,
+		Availability: Availability{Status: AvailabilityAvailable},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := product.Validate(); err != nil {
+		return nil, err
 	}
 
 	return product, nil
 }
+
+// Validate reports whether the product satisfies the domain invariants.
+func (p *Product) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return validationError("product name must not be empty")
+	}
+	if p.Price <= 0 {
+		return validationError("product price must be greater than zero")
+	}
+	if p.CategoryId == uuid.Nil {
+		return validationError("product category must be informed")
+	}
+
+	return nil
+}
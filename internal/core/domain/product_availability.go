@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// Availability statuses of a product.
+const (
+	AvailabilityAvailable = "available"
+	// AvailabilityUnavailable is for products temporarily out of stock, optionally until a known time.
+	AvailabilityUnavailable  = "unavailable"
+	AvailabilityDiscontinued = "discontinued"
+)
+
+// Availability tells whether a product can be ordered. A temporarily unavailable
+// product with BackAt becomes available again at that time without further changes.
+type Availability struct {
+	Status string     `json:"status" bson:"status"`
+	BackAt *time.Time `json:"back_at,omitempty" bson:"back_at,omitempty"`
+}
+
+// NewAvailability validates an availability set at now.
+func NewAvailability(status string, backAt *time.Time, now time.Time) (Availability, error) {
+	switch status {
+	case AvailabilityAvailable, AvailabilityDiscontinued:
+		if backAt != nil {
+			return Availability{}, validationError("back_at is only allowed for unavailable products")
+		}
+	case AvailabilityUnavailable:
+		if backAt != nil && !backAt.After(now) {
+			return Availability{}, validationError("back_at must be in the future")
+		}
+	default:
+		return Availability{}, validationError("invalid availability status %q, use available, unavailable or discontinued", status)
+	}
+	return Availability{Status: status, BackAt: backAt}, nil
+}
+
+// AvailableAt reports whether the product can be ordered at t. Products saved before
+// availability existed have no status and are available.
+func (a Availability) AvailableAt(t time.Time) bool {
+	switch a.Status {
+	case AvailabilityUnavailable:
+		return a.BackAt != nil && !t.Before(*a.BackAt)
+	case AvailabilityDiscontinued:
+		return false
+	default:
+		return true
+	}
+}
+
+// CheckOrderable returns a validation error when the product cannot be ordered at now.
+func (p *Product) CheckOrderable(now time.Time) error {
+	if p.Availability.AvailableAt(now) {
+		return nil
+	}
+	if p.Availability.Status == AvailabilityUnavailable && p.Availability.BackAt != nil {
+		return validationError("product %s is unavailable until %s", p.Name, p.Availability.BackAt.Format(time.RFC3339))
+	}
+	return validationError("product %s is %s", p.Name, p.Availability.Status)
+}
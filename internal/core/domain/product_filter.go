@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ProductFilter narrows a product list. Zero fields do not filter.
+type ProductFilter struct {
+	// Search keeps the products whose name or description contains its words, ignoring
+	// case and accents.
+	Search string
+	// CategoryIDs keeps the products of any of the categories.
+	CategoryIDs []uuid.UUID
+	MinPrice    *float64
+	MaxPrice    *float64
+	// AvailableAt keeps the products that can be ordered at that time, see
+	// Availability.AvailableAt.
+	AvailableAt time.Time
+}
+
+// Validate reports filters that cannot match any product because of a typo in the request.
+func (f ProductFilter) Validate() error {
+	if f.MinPrice != nil && *f.MinPrice < 0 {
+		return validationError("the minimum price must not be negative")
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return validationError("the minimum price must not exceed the maximum price")
+	}
+	return nil
+}
+
+// SearchTerms returns the folded words of Search, see FoldText.
+func (f ProductFilter) SearchTerms() []string {
+	return strings.Fields(FoldText(f.Search))
+}
+
+// Matches reports whether the product passes the filter. Every search term must be
+// contained in the product's SearchText.
+func (f ProductFilter) Matches(product Product) bool {
+	if len(f.CategoryIDs) > 0 && !slices.Contains(f.CategoryIDs, product.CategoryId) {
+		return false
+	}
+	if f.MinPrice != nil && product.Price < *f.MinPrice {
+		return false
+	}
+	if f.MaxPrice != nil && product.Price > *f.MaxPrice {
+		return false
+	}
+	if !f.AvailableAt.IsZero() && !product.Availability.AvailableAt(f.AvailableAt) {
+		return false
+	}
+	text := product.SearchText()
+	for _, term := range f.SearchTerms() {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchText returns the folded name and description the product is searched by.
+func (p *Product) SearchText() string {
+	return FoldText(p.Name + " " + p.Description)
+}
+
+// FoldText lowercases s and strips its accents, so "Pão" and "pao" compare equal.
+func FoldText(s string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(folded)
+}
+
+// Fields product lists can be sorted by. Popularity is the number of units ordered.
+const (
+	ProductSortCreatedAt  = "created_at"
+	ProductSortName       = "name"
+	ProductSortPrice      = "price"
+	ProductSortPopularity = "popularity"
+)
+
+// ProductSort orders a product list by one field, with the ID breaking ties so pages
+// do not overlap.
+type ProductSort struct {
+	Field      string
+	Descending bool
+}
+
+// ParseProductSort parses a field name, prefixed with - for descending order. An empty
+// value sorts by creation time, oldest first.
+func ParseProductSort(value string) (ProductSort, error) {
+	sort := ProductSort{Field: ProductSortCreatedAt}
+	if value == "" {
+		return sort, nil
+	}
+
+	sort.Field, sort.Descending = strings.CutPrefix(value, "-")
+	switch sort.Field {
+	case ProductSortCreatedAt, ProductSortName, ProductSortPrice, ProductSortPopularity:
+		return sort, nil
+	}
+	return ProductSort{}, validationError("products cannot be sorted by %q, use name, price, popularity or created_at, prefixed with - for descending order", sort.Field)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewProduct(t *testing.T) {
+	categoryID := uuid.New()
+	for _, tc := range []struct {
+		name       string
+		product    string
+		price      float64
+		categoryID uuid.UUID
+		wantErr    bool
+	}{
+		{"Valid", "X-Burger", 25.5, categoryID, false},
+		{"EmptyName", "", 25.5, categoryID, true},
+		{"BlankName", "   ", 25.5, categoryID, true},
+		{"ZeroPrice", "X-Burger", 0, categoryID, true},
+		{"NegativePrice", "X-Burger", -1, categoryID, true},
+		{"NoCategory", "X-Burger", 25.5, uuid.Nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			product, err := NewProduct(tc.product, tc.price, tc.categoryID, "Pão, hambúrguer e queijo", "")
+			if tc.wantErr {
+				if !errors.Is(err, ErrValidation) || product != nil {
+					t.Fatalf("NewProduct = %v, %v, want ErrValidation", product, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if product.ID == uuid.Nil || product.Availability.Status != AvailabilityAvailable || product.CreatedAt.IsZero() {
+				t.Errorf("NewProduct = %+v, want an ID, availability and creation time", product)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+
+	// WebhookMaxAttempts is the number of attempts after which a delivery is given up.
+	WebhookMaxAttempts = 10
+	// webhookMinSecretLength keeps HMAC signatures from being brute forced.
+	webhookMinSecretLength = 16
+)
+
+// WebhookEventTypes lists the event types partners can subscribe to; "*" subscribes to all of them.
+var WebhookEventTypes = []string{EventOrderCreated, EventOrderStatusChanged, EventProductPriceChanged}
+
+type WebhookSubscription struct {
+	ID         uuid.UUID `json:"id" bson:"_id"`
+	URL        string    `json:"url" bson:"url"`
+	EventTypes []string  `json:"event_types" bson:"event_types"`
+	Secret     string    `json:"-" bson:"secret"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// WebhookAttempt is one entry of the delivery log.
+type WebhookAttempt struct {
+	At           time.Time `json:"at" bson:"at"`
+	ResponseCode int       `json:"response_code" bson:"response_code"`
+	Error        string    `json:"error,omitempty" bson:"error"`
+	DurationMs   int64     `json:"duration_ms" bson:"duration_ms"`
+}
+
+// WebhookDelivery is the delivery of one event to one subscription.
+type WebhookDelivery struct {
+	ID             uuid.UUID        `json:"id" bson:"_id"`
+	SubscriptionID uuid.UUID        `json:"subscription_id" bson:"subscription_id"`
+	EventID        uuid.UUID        `json:"event_id" bson:"event_id"`
+	EventType      string           `json:"event_type" bson:"event_type"`
+	Payload        json.RawMessage  `json:"payload" bson:"payload" swaggertype:"object"`
+	Status         string           `json:"status" bson:"status"`
+	NextAttemptAt  time.Time        `json:"next_attempt_at" bson:"next_attempt_at"`
+	Attempts       []WebhookAttempt `json:"attempts" bson:"attempts"`
+	CreatedAt      time.Time        `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" bson:"updated_at"`
+}
+
+func NewWebhookSubscription(rawURL string, eventTypes []string, secret string) (*WebhookSubscription, error) {
+	now := time.Now()
+
+	subscription := &WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        rawURL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Validate reports whether the subscription satisfies the domain invariants.
+func (s *WebhookSubscription) Validate() error {
+	parsed, err := url.Parse(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return validationError("webhook URL must be an absolute http or https URL")
+	}
+
+	if len(s.EventTypes) == 0 {
+		return validationError("webhook must subscribe to at least one event type")
+	}
+	for _, eventType := range s.EventTypes {
+		if !isWebhookEventType(eventType) {
+			return validationError("unknown event type %q", eventType)
+		}
+	}
+
+	if len(s.Secret) < webhookMinSecretLength {
+		return validationError("webhook secret must have at least %d characters", webhookMinSecretLength)
+	}
+
+	return nil
+}
+
+// Matches reports whether the subscription wants to receive events of eventType.
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	for _, subscribed := range s.EventTypes {
+		if subscribed == "*" || subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebhookDelivery prepares the delivery of event to subscription. The ID is derived
+// from both, so dispatching the same event twice does not deliver it twice.
+func NewWebhookDelivery(subscription *WebhookSubscription, event Event) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	delivery := &WebhookDelivery{
+		ID:             uuid.NewSHA1(event.ID, subscription.ID[:]),
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		Attempts:       []WebhookAttempt{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	return delivery, nil
+}
+
+// RecordAttempt appends attempt to the log and moves the delivery to its next status.
+// Failed attempts are retried at retryAt until WebhookMaxAttempts is reached.
+func (d *WebhookDelivery) RecordAttempt(attempt WebhookAttempt, retryAt time.Time) {
+	d.Attempts = append(d.Attempts, attempt)
+	d.UpdatedAt = attempt.At
+
+	switch {
+	case attempt.Error == "" && attempt.ResponseCode >= 200 && attempt.ResponseCode <= 299:
+		d.Status = WebhookDeliverySucceeded
+	case len(d.Attempts) >= WebhookMaxAttempts:
+		d.Status = WebhookDeliveryFailed
+	default:
+		d.Status = WebhookDeliveryPending
+		d.NextAttemptAt = retryAt
+	}
+}
+
+// Redeliver schedules the delivery to be attempted again right away, whatever its status.
+// The attempt log is kept, so a delivery that already exhausted its attempts gets a single new one.
+func (d *WebhookDelivery) Redeliver() {
+	now := time.Now()
+	d.Status = WebhookDeliveryPending
+	d.NextAttemptAt = now
+	d.UpdatedAt = now
+}
+
+func isWebhookEventType(eventType string) bool {
+	if eventType == "*" {
+		return true
+	}
+	for _, known := range WebhookEventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"io"
+)
+
+// DatabaseBackup writes a consistent snapshot of the database while it keeps serving requests.
+type DatabaseBackup interface {
+	Backup(ctx context.Context, w io.Writer) error
+}
@@ -9,17 +9,23 @@ import (
 type CategoryRepository interface {
 	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
 	GetCategoryByID(ctx context.Context, id string) (*domain.Category, error)
-	GetCategories(ctx context.Context, page, limit int) ([]domain.Category, error)
+	GetCategories(ctx context.Context, page, limit int) ([]domain.Category, int64, error)
 	ReplaceCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
 	DeleteCategory(ctx context.Context, id string) error
 	UpdateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
 }
 
+// CreateCategoryRequest holds the fields a category is created with.
+type CreateCategoryRequest struct {
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description" json:"description"`
+}
+
 type CategoryService interface {
-	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
+	CreateCategory(ctx context.Context, category CreateCategoryRequest) (*domain.Category, error)
 	GetCategoryByID(ctx context.Context, id string) (*domain.Category, error)
-	GetCategories(ctx context.Context, page, limit int) ([]domain.Category, error)
-	ReplaceCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
+	GetCategories(ctx context.Context, page, size int) ([]domain.Category, int64, error)
+	ReplaceCategory(ctx context.Context, id string, category *domain.Category) (*domain.Category, error)
+	UpdateCategory(ctx context.Context, id string, category *domain.Category) (*domain.Category, error)
 	DeleteCategory(ctx context.Context, id string) error
-	UpdateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
 }
@@ -8,10 +8,17 @@ import (
 
 type ClientRepository interface {
 	CreateClient(ctx context.Context, client *domain.Client) (*domain.Client, error)
-	GetClientByCPF(ctx context.Context, cpf string) (*domain.Client, error)
+	GetClientByCPF(ctx context.Context, cpf domain.CPF) (*domain.Client, error)
+}
+
+// CreateClientRequest holds the fields a client is registered with.
+type CreateClientRequest struct {
+	Name string `json:"name" bson:"name"`
+	Cpf  string `json:"cpf" bson:"cpf"`
+	Mail string `json:"mail" bson:"mail"`
 }
 
 type ClientService interface {
-	CreateClient(ctx context.Context, client *domain.Client) (*domain.Client, error)
+	CreateClient(ctx context.Context, client CreateClientRequest) (*domain.Client, error)
 	GetClientByCPF(ctx context.Context, cpf string) (*domain.Client, error)
 }
@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+// OutboxRepository stores events in the same write as the change that raised them,
+// so they are delivered if and only if that change is committed.
+type OutboxRepository interface {
+	Save(ctx context.Context, events ...*domain.Event) error
+	// ClaimPending returns up to limit undelivered messages that are due, hiding them
+	// from other callers for the lease duration.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error
+}
+
+// EventPublisher delivers events to systems outside the API. Publish may be called more
+// than once for the same event, so consumers must deduplicate by event ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+type HealthService interface {
+	Ready(ctx context.Context) domain.HealthReport
+}
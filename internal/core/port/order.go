@@ -10,13 +10,33 @@ import (
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, product *domain.Order) (*domain.Order, error)
 	GetOrderByID(ctx context.Context, id string) (*domain.Order, error)
-	GetOrders(ctx context.Context, page, pageSize int) ([]domain.Order, error)
+	GetOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, page, pageSize int) ([]domain.Order, int64, error)
+	// GetOrdersByCursor returns up to query.Limit orders, newest first, after or before the query cursor.
+	GetOrdersByCursor(ctx context.Context, query domain.OrderCursorQuery) ([]domain.Order, error)
 	SetStatus(ctx context.Context, id uuid.UUID, status int, description string) error
+	// CountByStatus returns how many orders are in each status; statuses without orders are omitted.
+	CountByStatus(ctx context.Context) (map[int]int64, error)
+}
+
+// ProductItem is a product ordered and its quantity.
+type ProductItem struct {
+	ID          string `json:"id"`
+	Quantity    int64  `json:"quantity"`
+	ProductName string `json:"product_name" swaggerignore:"true"`
+}
+
+// CreateOrderRequest is an order as placed by a client, before prices are looked up.
+type CreateOrderRequest struct {
+	Client   string        `json:"client"`
+	Products []ProductItem `json:"products"`
 }
 
 type OrderService interface {
-	CreateOrder(ctx context.Context, product *domain.Order) ([]domain.Order, error)
+	CreateOrder(ctx context.Context, order CreateOrderRequest) (*domain.Order, error)
 	GetOrderByID(ctx context.Context, id string) (*domain.Order, error)
-	GetOrders(ctx context.Context, page, pageSize int) ([]domain.Order, error)
-	SetStatus(ctx context.Context, id uuid.UUID, status int, description string) error
+	GetOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, page, size int) ([]domain.Order, int64, error)
+	// GetOrdersByCursor pages through the filtered orders with the tokens of the Next and
+	// Prev cursors of a previous page.
+	GetOrdersByCursor(ctx context.Context, filter domain.OrderFilter, after, before string, size int) (*domain.OrderCursorPage, error)
+	SetOrderStatus(ctx context.Context, id string, status int) (*domain.OrderStatus, error)
 }
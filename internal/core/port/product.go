@@ -2,24 +2,53 @@ package port
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
 )
 
 type ProductRepository interface {
 	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	GetProductByID(ctx context.Context, id string) (*domain.Product, error)
-	GetProducts(ctx context.Context, categoryId string, page, limit int) ([]domain.Product, error)
+	GetProducts(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, page, limit int) ([]domain.Product, int64, error)
 	ReplaceProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	DeleteProduct(ctx context.Context, id string) error
+	// SetAvailability changes the availability of the product and its update time.
+	SetAvailability(ctx context.Context, id uuid.UUID, availability domain.Availability) error
+	// RecordSales adds the quantity of each item to the popularity of its product.
+	// Items of products that no longer exist are ignored.
+	RecordSales(ctx context.Context, items []domain.OrderItem) error
+}
+
+// CreateProductRequest holds the fields a product is created or replaced with. Updates
+// change only the fields that are set.
+type CreateProductRequest struct {
+	CategoryId  uuid.UUID `json:"category_id"`
+	Name        string    `json:"name"`
+	Price       float64   `json:"price"`
+	Description string    `json:"description"`
+	Image       string    `json:"image"`
+}
+
+// SetAvailabilityRequest changes whether a product can be ordered.
+type SetAvailabilityRequest struct {
+	// Status is available, unavailable or discontinued.
+	Status string `json:"status" example:"unavailable"`
+	// BackAt is when an unavailable product can be ordered again, if known.
+	BackAt *time.Time `json:"back_at,omitempty"`
 }
 
 type ProductService interface {
-	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
+	CreateProduct(ctx context.Context, product CreateProductRequest) (*domain.Product, error)
 	GetProductByID(ctx context.Context, id string) (*domain.Product, error)
-	GetProducts(ctx context.Context, categoryId string, page, limit int) ([]domain.Product, error)
-	ReplaceProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
-	UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
+	GetProducts(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, page, size int) ([]domain.Product, int64, error)
+	ReplaceProduct(ctx context.Context, id string, product CreateProductRequest) (*domain.Product, error)
+	UpdateProduct(ctx context.Context, id string, product CreateProductRequest) (*domain.Product, error)
 	DeleteProduct(ctx context.Context, id string) error
+	SetAvailability(ctx context.Context, id string, availability SetAvailabilityRequest) (*domain.Product, error)
+	// RecordSales counts the items of an order towards the popularity of their products,
+	// within the unit of work of ctx when there is one.
+	RecordSales(ctx context.Context, items []domain.OrderItem) error
 }
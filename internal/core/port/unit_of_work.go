@@ -0,0 +1,9 @@
+package port
+
+import "context"
+
+// UnitOfWork runs fn so that every repository call made with the context it receives
+// is committed or discarded together. Calls nested inside fn join the outer unit.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
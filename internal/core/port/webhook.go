@@ -0,0 +1,53 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+)
+
+type WebhookSubscriptionRepository interface {
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context, page, limit int) ([]domain.WebhookSubscription, int64, error)
+	GetSubscriptionsByEventType(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error)
+	ReplaceSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+}
+
+type WebhookDeliveryRepository interface {
+	// CreateDelivery ignores deliveries whose ID already exists.
+	CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, subscriptionID string, page, limit int) ([]domain.WebhookDelivery, int64, error)
+	// ClaimDue returns up to limit pending deliveries that are due, hiding them from
+	// other callers for the lease duration.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	ReplaceDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+// WebhookSender posts a signed webhook payload and returns the HTTP status code of the response.
+type WebhookSender interface {
+	Send(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error)
+}
+
+// CreateWebhookSubscriptionRequest holds the fields a webhook subscription is created or
+// replaced with.
+type CreateWebhookSubscriptionRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, subscription CreateWebhookSubscriptionRequest) (*domain.WebhookSubscription, error)
+	ReplaceSubscription(ctx context.Context, id string, subscription CreateWebhookSubscriptionRequest) (*domain.WebhookSubscription, error)
+	GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context, page, size int) ([]domain.WebhookSubscription, int64, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	GetDeliveries(ctx context.Context, subscriptionID string, page, size int) ([]domain.WebhookDelivery, int64, error)
+	GetDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	// Redeliver schedules a failed or succeeded delivery to be sent again.
+	Redeliver(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+}
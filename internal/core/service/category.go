@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
 	"github.com/mfritschdotgo/techchallenge/internal/core/port"
 )
@@ -15,14 +14,16 @@ type CategoryService struct {
 	categoryRepo port.CategoryRepository
 }
 
+var _ port.CategoryService = (*CategoryService)(nil)
+
 func NewCategoryService(repo port.CategoryRepository) *CategoryService {
 	return &CategoryService{
 		categoryRepo: repo,
 	}
 }
 
-func (s *CategoryService) CreateCategory(ctx context.Context, dto dto.CreateCategoryRequest) (*domain.Category, error) {
-	category, err := domain.NewCategory(dto.Name, dto.Description)
+func (s *CategoryService) CreateCategory(ctx context.Context, request port.CreateCategoryRequest) (*domain.Category, error) {
+	category, err := domain.NewCategory(request.Name, request.Description)
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +52,10 @@ func (s *CategoryService) ReplaceCategory(ctx context.Context, id string, catego
 	categoryDto.Description = category.Description
 	categoryDto.UpdatedAt = time.Now()
 
+	if err := categoryDto.Validate(); err != nil {
+		return nil, err
+	}
+
 	if _, err = s.categoryRepo.ReplaceCategory(ctx, categoryDto); err != nil {
 		return nil, fmt.Errorf("failed to replace category: %w", err)
 	}
@@ -90,20 +95,15 @@ func (s *CategoryService) GetCategoryByID(ctx context.Context, id string) (*doma
 	return category, nil
 }
 
-func (s *CategoryService) GetCategories(ctx context.Context, page, size int) ([]domain.Category, error) {
-	if page <= 0 {
-		page = 1
-	}
-	if size <= 0 {
-		size = 10
-	}
+func (s *CategoryService) GetCategories(ctx context.Context, page, size int) ([]domain.Category, int64, error) {
+	page, size = domain.NormalizePage(page, size)
 
-	categories, err := s.categoryRepo.GetCategories(ctx, page, size)
+	categories, total, err := s.categoryRepo.GetCategories(ctx, page, size)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return categories, nil
+	return categories, total, nil
 }
 
 func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
@@ -120,13 +120,13 @@ func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
 }
 
 func (s *CategoryService) InitializeCategories(ctx context.Context) error {
-	categories, err := s.GetCategories(ctx, 1, 1)
+	_, total, err := s.GetCategories(ctx, 1, 1)
 	if err != nil {
 		return err
 	}
 
-	if len(categories) == 0 {
-		initialCategories := []dto.CreateCategoryRequest{
+	if total == 0 {
+		initialCategories := []port.CreateCategoryRequest{
 			{Name: "Lanche", Description: "Categoria de Lanches"},
 			{Name: "Acompanhamento", Description: "Categoria de Acompanhamentos"},
 			{Name: "Bebida", Description: "Categoria de Bebidas"},
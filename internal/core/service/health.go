@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+var _ port.HealthService = (*HealthService)(nil)
+
+// HealthCheck returns an error when the dependency it checks cannot serve requests.
+type HealthCheck func(ctx context.Context) error
+
+// HealthService runs the readiness checks registered at startup. Once Drain is called
+// it reports draining so load balancers stop routing to the instance before it shuts down.
+type HealthService struct {
+	names    []string
+	checks   []HealthCheck
+	draining atomic.Bool
+}
+
+func NewHealthService() *HealthService {
+	return &HealthService{}
+}
+
+// Register adds a check reported under name. It must not be called once the API is serving.
+func (s *HealthService) Register(name string, check HealthCheck) {
+	s.names = append(s.names, name)
+	s.checks = append(s.checks, check)
+}
+
+// Drain makes every following readiness report fail.
+func (s *HealthService) Drain() {
+	s.draining.Store(true)
+}
+
+// Ready runs all checks concurrently, each bounded by its own timeout.
+func (s *HealthService) Ready(ctx context.Context) domain.HealthReport {
+	results := make([]domain.DependencyHealth, len(s.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := domain.HealthReport{
+		Status: domain.HealthUp,
+		Checks: make(map[string]domain.DependencyHealth, len(s.checks)),
+	}
+	for i, name := range s.names {
+		report.Checks[name] = results[i]
+		if results[i].Status != domain.HealthUp {
+			report.Status = domain.HealthDown
+		}
+	}
+	if s.draining.Load() {
+		report.Status = domain.HealthDraining
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check HealthCheck) domain.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := domain.DependencyHealth{
+		Status:    domain.HealthUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = domain.HealthDown
+		result.Error = err.Error()
+	}
+	return result
+}
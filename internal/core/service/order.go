@@ -3,44 +3,76 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
 	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
 )
 
 type OrderService struct {
 	orderRepo      port.OrderRepository
-	clientService  *ClientService
-	productService *ProductService
+	outbox         port.OutboxRepository
+	uow            port.UnitOfWork
+	clientService  port.ClientService
+	productService port.ProductService
 }
 
-func NewOrderService(repo port.OrderRepository, clientService *ClientService, productService *ProductService) *OrderService {
+var _ port.OrderService = (*OrderService)(nil)
+
+func NewOrderService(repo port.OrderRepository, outbox port.OutboxRepository, uow port.UnitOfWork, clientService port.ClientService, productService port.ProductService) *OrderService {
 	return &OrderService{
 		orderRepo:      repo,
+		outbox:         outbox,
+		uow:            uow,
 		clientService:  clientService,
 		productService: productService,
 	}
 }
 
-func (s *OrderService) CreateOrder(ctx context.Context, dto dto.CreateOrderRequest) (*domain.Order, error) {
-	_, err := s.clientService.GetClientByCPF(ctx, dto.Client)
+// CreateOrder validates the client and products and saves the order, the sales of its
+// products and its OrderCreated event in a single unit of work.
+func (s *OrderService) CreateOrder(ctx context.Context, request port.CreateOrderRequest) (*domain.Order, error) {
+	var savedOrder *domain.Order
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		order, err := s.createOrder(ctx, request)
+		if err != nil {
+			return err
+		}
+		savedOrder = order
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("order created", "order_id", savedOrder.ID, "client", savedOrder.Client, "total", savedOrder.Total)
+	return savedOrder, nil
+}
+
+func (s *OrderService) createOrder(ctx context.Context, request port.CreateOrderRequest) (*domain.Order, error) {
+	_, err := s.clientService.GetClientByCPF(ctx, request.Client)
 	if err != nil {
 		return nil, fmt.Errorf("client validation failed: %w", err)
 	}
 
+	now := time.Now()
 	total := 0.0
 	productDetails := make(map[string]struct {
 		Price float64
 		Name  string
 	})
 
-	for _, item := range dto.Products {
+	for _, item := range request.Products {
 		product, err := s.productService.GetProductByID(ctx, item.ID)
 		if err != nil {
 			return nil, fmt.Errorf("product validation failed for product ID %s: %w", item.ID, err)
 		}
+		if err := product.CheckOrderable(now); err != nil {
+			return nil, err
+		}
 		productDetails[item.ID] = struct {
 			Price float64
 			Name  string
@@ -51,9 +83,9 @@ func (s *OrderService) CreateOrder(ctx context.Context, dto dto.CreateOrderReque
 		total += product.Price * float64(item.Quantity)
 	}
 
-	items := ConvertDTOtoSlice(dto.Products, productDetails)
+	items := ConvertDTOtoSlice(request.Products, productDetails)
 
-	order, err := domain.NewOrder(dto.Client, items, 0, total, "created")
+	order, err := domain.NewOrder(request.Client, items, domain.OrderStatusCreated, total, "created")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create order instance: %w", err)
 	}
@@ -63,10 +95,28 @@ func (s *OrderService) CreateOrder(ctx context.Context, dto dto.CreateOrderReque
 		return nil, fmt.Errorf("failed to save order: %w", err)
 	}
 
+	if err := s.productService.RecordSales(ctx, savedOrder.Items); err != nil {
+		return nil, err
+	}
+
+	event, err := domain.NewEvent(domain.EventOrderCreated, savedOrder.ID, domain.OrderCreated{
+		OrderID: savedOrder.ID,
+		Client:  savedOrder.Client,
+		Items:   savedOrder.Items,
+		Total:   savedOrder.Total,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.outbox.Save(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to save order event: %w", err)
+	}
+
 	return savedOrder, nil
 }
 
-func ConvertDTOtoSlice(dtoProducts []dto.ProductItem, productDetails map[string]struct {
+func ConvertDTOtoSlice(dtoProducts []port.ProductItem, productDetails map[string]struct {
 	Price float64
 	Name  string
 }) []domain.OrderItem {
@@ -98,21 +148,85 @@ func (s *OrderService) GetOrderByID(ctx context.Context, id string) (*domain.Ord
 	return order, nil
 }
 
-func (s *OrderService) GetOrders(ctx context.Context, page, size int) ([]domain.Order, error) {
-	if page <= 0 {
-		page = 1
+func (s *OrderService) GetOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, page, size int) ([]domain.Order, int64, error) {
+	page, size = domain.NormalizePage(page, size)
+	if err := s.checkFilter(ctx, filter); err != nil {
+		return nil, 0, err
 	}
-	if size <= 0 {
-		size = 10
+
+	orders, total, err := s.orderRepo.GetOrders(ctx, filter, sort, page, size)
+
+	if err != nil {
+		return nil, 0, err
 	}
 
-	orders, err := s.orderRepo.GetOrders(ctx, page, size)
+	return orders, total, nil
+}
+
+func (s *OrderService) GetOrdersByCursor(ctx context.Context, filter domain.OrderFilter, after, before string, size int) (*domain.OrderCursorPage, error) {
+	if after != "" && before != "" {
+		return nil, fmt.Errorf("%w: after and before cannot be combined", domain.ErrValidation)
+	}
+	_, size = domain.NormalizePage(1, size)
+	if err := s.checkFilter(ctx, filter); err != nil {
+		return nil, err
+	}
 
+	// One extra order tells whether another page follows.
+	query := domain.OrderCursorQuery{Filter: filter, Limit: size + 1}
+	if after != "" {
+		position, err := domain.ParseOrderCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		query.After = &position
+	}
+	if before != "" {
+		position, err := domain.ParseOrderCursor(before)
+		if err != nil {
+			return nil, err
+		}
+		query.Before = &position
+	}
+
+	orders, err := s.orderRepo.GetOrdersByCursor(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	return orders, nil
+	page := &domain.OrderCursorPage{Orders: orders}
+	more := len(orders) > size
+	if query.Before != nil {
+		if more {
+			page.Orders = orders[1:]
+		}
+		// The page came from the one at the cursor, so older orders follow it.
+		page.Next = query.Before
+	} else if more {
+		page.Orders = orders[:size]
+	}
+	if len(page.Orders) > 0 {
+		first, last := domain.NewOrderCursor(page.Orders[0]), domain.NewOrderCursor(page.Orders[len(page.Orders)-1])
+		page.Prev = &first
+		if more || query.Before != nil {
+			page.Next = &last
+		}
+	}
+	return page, nil
+}
+
+// checkFilter validates the filter and, like the product list does for its category,
+// that the client it filters by exists.
+func (s *OrderService) checkFilter(ctx context.Context, filter domain.OrderFilter) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	if filter.Client != "" {
+		if _, err := s.clientService.GetClientByCPF(ctx, string(filter.Client)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *OrderService) SetOrderStatus(ctx context.Context, id string, status int) (*domain.OrderStatus, error) {
@@ -121,23 +235,42 @@ func (s *OrderService) SetOrderStatus(ctx context.Context, id string, status int
 		return nil, fmt.Errorf("invalid ID format: %w", err)
 	}
 
-	_, err = s.orderRepo.GetOrderByID(ctx, uuidID.String())
+	orderStatus, err := domain.SetStatus(status)
 
 	if err != nil {
-		return nil, fmt.Errorf("order not found: %w", err)
+		return nil, err
 	}
 
-	orderStatus, err := domain.SetStatus(status)
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetOrderByID(ctx, uuidID.String())
+		if err != nil {
+			return fmt.Errorf("order not found: %w", err)
+		}
 
-	if err != nil {
-		return nil, fmt.Errorf(err.Error())
-	}
+		if err := s.orderRepo.SetStatus(ctx, uuidID, orderStatus.Status, orderStatus.StatusDescription); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
 
-	err = s.orderRepo.SetStatus(ctx, uuidID, orderStatus.Status, orderStatus.StatusDescription)
+		event, err := domain.NewEvent(domain.EventOrderStatusChanged, order.ID, domain.OrderStatusChanged{
+			OrderID:           order.ID,
+			PreviousStatus:    order.Status,
+			Status:            orderStatus.Status,
+			StatusDescription: orderStatus.StatusDescription,
+		})
+		if err != nil {
+			return err
+		}
 
+		if err := s.outbox.Save(ctx, event); err != nil {
+			return fmt.Errorf("failed to save order event: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update order status: %w", err)
+		return nil, err
 	}
 
+	logging.FromContext(ctx).Info("order status changed", "order_id", uuidID, "status", orderStatus.StatusDescription)
 	return orderStatus, nil
 }
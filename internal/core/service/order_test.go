@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/memory"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+)
+
+// failingOutbox fails Save while err is set.
+type failingOutbox struct {
+	port.OutboxRepository
+	err error
+}
+
+func (o *failingOutbox) Save(ctx context.Context, events ...*domain.Event) error {
+	if o.err != nil {
+		return o.err
+	}
+	return o.OutboxRepository.Save(ctx, events...)
+}
+
+func TestCreateOrderDiscardsSalesWithTheOrder(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := memory.NewCategoryRepository()
+	productRepo := memory.NewProductRepository()
+	clientRepo := memory.NewClientRepository()
+	orderRepo := memory.NewOrderRepository()
+	outboxRepo := memory.NewOutboxRepository()
+	outbox := &failingOutbox{OutboxRepository: outboxRepo}
+	uow := memory.NewUnitOfWork()
+
+	categories := service.NewCategoryService(categoryRepo)
+	products := service.NewProductService(productRepo, outbox, uow, categories)
+	clients := service.NewClientService(clientRepo)
+	orders := service.NewOrderService(orderRepo, outbox, uow, clients, products)
+
+	category, err := categories.CreateCategory(ctx, port.CreateCategoryRequest{Name: "Lanche", Description: "Hamburgers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	burger, err := products.CreateProduct(ctx, port.CreateProductRequest{CategoryId: category.ID, Name: "X-Burger", Price: 25.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bacon, err := products.CreateProduct(ctx, port.CreateProductRequest{CategoryId: category.ID, Name: "X-Bacon", Price: 29})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := clients.CreateClient(ctx, port.CreateClientRequest{Name: "Ana", Cpf: "529.982.247-25", Mail: "ana@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outbox.err = errors.New("outbox unavailable")
+	_, err = orders.CreateOrder(ctx, port.CreateOrderRequest{
+		Client:   string(client.Cpf),
+		Products: []port.ProductItem{{ID: burger.ID.String(), Quantity: 5}},
+	})
+	if !errors.Is(err, outbox.err) {
+		t.Fatalf("CreateOrder error = %v, want the outbox failure", err)
+	}
+
+	outbox.err = nil
+	if _, err := orders.CreateOrder(ctx, port.CreateOrderRequest{
+		Client:   string(client.Cpf),
+		Products: []port.ProductItem{{ID: bacon.ID.String(), Quantity: 1}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, total, err := orders.GetOrders(ctx, domain.OrderFilter{}, domain.OrderSort{}, 1, 10); err != nil || total != 1 {
+		t.Fatalf("GetOrders = %d orders, %v, want 1", total, err)
+	}
+	// Had the sales of the discarded order been kept, the burger would be the most popular.
+	popular, _, err := products.GetProducts(ctx, domain.ProductFilter{}, domain.ProductSort{Field: domain.ProductSortPopularity, Descending: true}, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(popular) != 1 || popular[0].ID != bacon.ID {
+		t.Fatalf("most popular product = %v, want %s", popular, bacon.Name)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+)
+
+const (
+	outboxBatchSize  = 50
+	outboxLease      = 30 * time.Second
+	outboxMinBackoff = time.Second
+	outboxMaxBackoff = 5 * time.Minute
+)
+
+// OutboxRelay delivers the events stored in the outbox to the event publisher.
+// An event is marked as delivered only after Publish succeeds, so delivery is at least once;
+// failed deliveries are retried with exponential backoff.
+type OutboxRelay struct {
+	outbox    port.OutboxRepository
+	publisher port.EventPublisher
+	interval  time.Duration
+	running   atomic.Bool
+}
+
+func NewOutboxRelay(outbox port.OutboxRepository, publisher port.EventPublisher, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		interval:  interval,
+	}
+}
+
+// Running reports whether Run is looping, for readiness checks.
+func (r *OutboxRelay) Running() bool {
+	return r.running.Load()
+}
+
+// Run relays pending events every interval until ctx is cancelled. A batch already
+// being relayed when that happens is finished first, so Run may return after ctx is done.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	r.running.Store(true)
+	defer r.running.Store(false)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayPending(context.WithoutCancel(ctx)); err != nil {
+			logging.FromContext(ctx).Error("outbox relay failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes one batch of due events and returns how many were delivered.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	messages, err := r.outbox.ClaimPending(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, message := range messages {
+		if err := r.publisher.Publish(ctx, message.Event); err != nil {
+			next := time.Now().Add(backoff(message.Attempts, outboxMinBackoff, outboxMaxBackoff))
+			logging.FromContext(ctx).Warn("event delivery failed, will retry",
+				"event_id", message.ID, "event_type", message.Type, "attempts", message.Attempts+1, "next_attempt_at", next, "error", err)
+			if err := r.outbox.MarkFailed(ctx, message.ID, err.Error(), next); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+
+		if err := r.outbox.MarkDelivered(ctx, message.ID); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// backoff doubles the delay for every previous attempt, capped at max.
+func backoff(attempts int, min, max time.Duration) time.Duration {
+	delay := min
+	for i := 0; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
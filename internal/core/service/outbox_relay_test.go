@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/memory"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+)
+
+// relayOutbox wraps the memory outbox to observe the relay. It records the wait before
+// every retry and fails MarkDelivered while failMarkDelivered is positive. With dueAtOnce
+// set, failed and claimed events are due again right away, so tests need not wait.
+type relayOutbox struct {
+	*memory.OutboxRepository
+	retries           []time.Duration
+	failMarkDelivered int
+	dueAtOnce         bool
+}
+
+func (o *relayOutbox) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	if o.dueAtOnce {
+		lease = 0
+	}
+	return o.OutboxRepository.ClaimPending(ctx, limit, lease)
+}
+
+func (o *relayOutbox) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	o.retries = append(o.retries, time.Until(nextAttemptAt))
+	if o.dueAtOnce {
+		nextAttemptAt = time.Now()
+	}
+	return o.OutboxRepository.MarkFailed(ctx, id, reason, nextAttemptAt)
+}
+
+func (o *relayOutbox) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	if o.failMarkDelivered > 0 {
+		o.failMarkDelivered--
+		return errors.New("outbox unavailable")
+	}
+	return o.OutboxRepository.MarkDelivered(ctx, id)
+}
+
+// flakyPublisher fails its first failures calls and records the ID of every event it is given.
+type flakyPublisher struct {
+	failures int
+	attempts []uuid.UUID
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, event domain.Event) error {
+	p.attempts = append(p.attempts, event.ID)
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	return nil
+}
+
+func saveEvent(t *testing.T, outbox *relayOutbox) *domain.Event {
+	t.Helper()
+	orderID := uuid.New()
+	event, err := domain.NewEvent(domain.EventOrderCreated, orderID, domain.OrderCreated{OrderID: orderID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Save(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func relay(t *testing.T, relay *service.OutboxRelay, want int) {
+	t.Helper()
+	delivered, err := relay.RelayPending(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivered != want {
+		t.Fatalf("RelayPending delivered %d events, want %d", delivered, want)
+	}
+}
+
+func TestRelayPendingDeliversEachEventOnce(t *testing.T) {
+	outbox := &relayOutbox{OutboxRepository: memory.NewOutboxRepository()}
+	publisher := &flakyPublisher{}
+	r := service.NewOutboxRelay(outbox, publisher, time.Second)
+
+	first, second := saveEvent(t, outbox), saveEvent(t, outbox)
+
+	relay(t, r, 2)
+	relay(t, r, 0)
+
+	if len(publisher.attempts) != 2 || publisher.attempts[0] != first.ID || publisher.attempts[1] != second.ID {
+		t.Errorf("published %v, want %s then %s", publisher.attempts, first.ID, second.ID)
+	}
+}
+
+func TestRelayWaitsForTheBackoffBeforeRetrying(t *testing.T) {
+	outbox := &relayOutbox{OutboxRepository: memory.NewOutboxRepository()}
+	publisher := &flakyPublisher{failures: 1}
+	r := service.NewOutboxRelay(outbox, publisher, time.Second)
+	saveEvent(t, outbox)
+
+	relay(t, r, 0)
+	relay(t, r, 0)
+
+	if len(publisher.attempts) != 1 {
+		t.Fatalf("published %d times before the retry was due, want once", len(publisher.attempts))
+	}
+}
+
+func TestRelayBacksOffExponentially(t *testing.T) {
+	outbox := &relayOutbox{OutboxRepository: memory.NewOutboxRepository(), dueAtOnce: true}
+	publisher := &flakyPublisher{failures: 3}
+	r := service.NewOutboxRelay(outbox, publisher, time.Second)
+	event := saveEvent(t, outbox)
+
+	for range 3 {
+		relay(t, r, 0)
+	}
+	relay(t, r, 1)
+	relay(t, r, 0)
+
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		if got := outbox.retries[i]; got < want-100*time.Millisecond || got > want {
+			t.Errorf("retry %d after %s, want %s", i+1, got, want)
+		}
+	}
+	if len(publisher.attempts) != 4 {
+		t.Fatalf("published %d times, want 4", len(publisher.attempts))
+	}
+	for _, id := range publisher.attempts {
+		if id != event.ID {
+			t.Errorf("published event %s, want every attempt to carry %s", id, event.ID)
+		}
+	}
+}
+
+func TestRelayRedeliversWhenDeliveryIsNotRecorded(t *testing.T) {
+	outbox := &relayOutbox{OutboxRepository: memory.NewOutboxRepository(), dueAtOnce: true, failMarkDelivered: 1}
+	publisher := &flakyPublisher{}
+	r := service.NewOutboxRelay(outbox, publisher, time.Second)
+	event := saveEvent(t, outbox)
+
+	// The event reached the publisher but was not marked delivered, as if the API had
+	// crashed in between, so it is published again once its claim expires.
+	if _, err := r.RelayPending(context.Background()); err == nil {
+		t.Fatal("RelayPending ignored the failure to mark the event delivered")
+	}
+	relay(t, r, 1)
+	relay(t, r, 0)
+
+	if len(publisher.attempts) != 2 || publisher.attempts[0] != event.ID || publisher.attempts[1] != event.ID {
+		t.Errorf("published %v, want %s twice", publisher.attempts, event.ID)
+	}
+}
@@ -6,30 +6,35 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/mfritschdotgo/techchallenge/internal/adapter/handler/dto"
 	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
 	"github.com/mfritschdotgo/techchallenge/internal/core/port"
 )
 
 type ProductService struct {
 	productRepo     port.ProductRepository
-	categoryService *CategoryService
+	outbox          port.OutboxRepository
+	uow             port.UnitOfWork
+	categoryService port.CategoryService
 }
 
-func NewProductService(repo port.ProductRepository, categoryService *CategoryService) *ProductService {
+var _ port.ProductService = (*ProductService)(nil)
+
+func NewProductService(repo port.ProductRepository, outbox port.OutboxRepository, uow port.UnitOfWork, categoryService port.CategoryService) *ProductService {
 	return &ProductService{
 		productRepo:     repo,
+		outbox:          outbox,
+		uow:             uow,
 		categoryService: categoryService,
 	}
 }
 
-func (s *ProductService) CreateProduct(ctx context.Context, dto dto.CreateProductRequest) (*domain.Product, error) {
+func (s *ProductService) CreateProduct(ctx context.Context, request port.CreateProductRequest) (*domain.Product, error) {
 
-	if _, err := s.categoryService.GetCategoryByID(ctx, dto.CategoryId.String()); err != nil {
+	if _, err := s.categoryService.GetCategoryByID(ctx, request.CategoryId.String()); err != nil {
 		return nil, fmt.Errorf("category validation failed: %w", err)
 	}
 
-	product, err := domain.NewProduct(dto.Name, dto.Price, dto.CategoryId, dto.Description, dto.Image)
+	product, err := domain.NewProduct(request.Name, request.Price, request.CategoryId, request.Description, request.Image)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +46,7 @@ func (s *ProductService) CreateProduct(ctx context.Context, dto dto.CreateProduc
 	return product, nil
 }
 
-func (s *ProductService) ReplaceProduct(ctx context.Context, id string, productDto dto.CreateProductRequest) (*domain.Product, error) {
+func (s *ProductService) ReplaceProduct(ctx context.Context, id string, productDto port.CreateProductRequest) (*domain.Product, error) {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid ID format: %w", err)
@@ -56,6 +61,8 @@ func (s *ProductService) ReplaceProduct(ctx context.Context, id string, productD
 		return nil, fmt.Errorf(err.Error())
 	}
 
+	oldPrice := product.Price
+
 	product.Name = productDto.Name
 	product.Price = productDto.Price
 	product.CategoryId = productDto.CategoryId
@@ -63,14 +70,18 @@ func (s *ProductService) ReplaceProduct(ctx context.Context, id string, productD
 	product.Image = productDto.Image
 	product.UpdatedAt = time.Now()
 
-	if _, err := s.productRepo.ReplaceProduct(ctx, product); err != nil {
-		return nil, fmt.Errorf("failed to update product: %w", err)
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.replaceProduct(ctx, product, oldPrice); err != nil {
+		return nil, err
 	}
 
 	return product, nil
 }
 
-func (s *ProductService) UpdateProduct(ctx context.Context, id string, productDto dto.CreateProductRequest) (*domain.Product, error) {
+func (s *ProductService) UpdateProduct(ctx context.Context, id string, productDto port.CreateProductRequest) (*domain.Product, error) {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid ID format: %w", err)
@@ -81,6 +92,8 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id string, productDt
 		return nil, fmt.Errorf(err.Error())
 	}
 
+	oldPrice := product.Price
+
 	if productDto.Name != "" {
 		product.Name = productDto.Name
 	}
@@ -104,13 +117,45 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id string, productDt
 
 	product.UpdatedAt = time.Now()
 
-	if _, err := s.productRepo.ReplaceProduct(ctx, product); err != nil {
-		return nil, fmt.Errorf("failed to update product: %w", err)
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.replaceProduct(ctx, product, oldPrice); err != nil {
+		return nil, err
 	}
 
 	return product, nil
 }
 
+// replaceProduct saves product and, when its price changed, raises ProductPriceChanged in the same unit of work.
+func (s *ProductService) replaceProduct(ctx context.Context, product *domain.Product, oldPrice float64) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.productRepo.ReplaceProduct(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+
+		if product.Price == oldPrice {
+			return nil
+		}
+
+		event, err := domain.NewEvent(domain.EventProductPriceChanged, product.ID, domain.ProductPriceChanged{
+			ProductID: product.ID,
+			OldPrice:  oldPrice,
+			NewPrice:  product.Price,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.outbox.Save(ctx, event); err != nil {
+			return fmt.Errorf("failed to save product event: %w", err)
+		}
+
+		return nil
+	})
+}
+
 func (s *ProductService) GetProductByID(ctx context.Context, id string) (*domain.Product, error) {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
@@ -125,25 +170,54 @@ func (s *ProductService) GetProductByID(ctx context.Context, id string) (*domain
 	return product, nil
 }
 
-func (s *ProductService) GetProducts(ctx context.Context, category string, page, size int) ([]domain.Product, error) {
-	if page <= 0 {
-		page = 1
+func (s *ProductService) GetProducts(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, page, size int) ([]domain.Product, int64, error) {
+	page, size = domain.NormalizePage(page, size)
+
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
 	}
-	if size <= 0 {
-		size = 10
+	for _, category := range filter.CategoryIDs {
+		if _, err := s.categoryService.GetCategoryByID(ctx, category.String()); err != nil {
+			return nil, 0, err
+		}
+	}
+	products, total, err := s.productRepo.GetProducts(ctx, filter, sort, page, size)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error retrieving products: %w", err)
 	}
 
-	if category != "" {
-		if _, err := s.categoryService.GetCategoryByID(ctx, category); err != nil {
-			return nil, fmt.Errorf(err.Error())
-		}
+	return products, total, nil
+}
+
+// SetAvailability lets the kitchen take a product off the kiosks, and put it back,
+// without changing anything else about it.
+func (s *ProductService) SetAvailability(ctx context.Context, id string, request port.SetAvailabilityRequest) (*domain.Product, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ID format", domain.ErrValidation)
 	}
-	products, err := s.productRepo.GetProducts(ctx, category, page, size)
+
+	availability, err := domain.NewAvailability(request.Status, request.BackAt, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving products: %w", err)
+		return nil, err
 	}
 
-	return products, nil
+	if err := s.productRepo.SetAvailability(ctx, uuidID, availability); err != nil {
+		return nil, err
+	}
+
+	return s.productRepo.GetProductByID(ctx, uuidID.String())
+}
+
+// RecordSales counts items towards the popularity of their products. Called while
+// creating an order, it joins the order's unit of work, so the sales are discarded with it.
+func (s *ProductService) RecordSales(ctx context.Context, items []domain.OrderItem) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.RecordSales(ctx, items); err != nil {
+			return fmt.Errorf("failed to record product sales: %w", err)
+		}
+		return nil
+	})
 }
 
 func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/repository/memory"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+)
+
+func TestSetAvailability(t *testing.T) {
+	ctx := context.Background()
+	uow := memory.NewUnitOfWork()
+	outbox := memory.NewOutboxRepository()
+	categories := service.NewCategoryService(memory.NewCategoryRepository())
+	products := service.NewProductService(memory.NewProductRepository(), outbox, uow, categories)
+	clients := service.NewClientService(memory.NewClientRepository())
+	orders := service.NewOrderService(memory.NewOrderRepository(), outbox, uow, clients, products)
+
+	category, err := categories.CreateCategory(ctx, port.CreateCategoryRequest{Name: "Lanche", Description: "Hamburgers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	burger, err := products.CreateProduct(ctx, port.CreateProductRequest{CategoryId: category.ID, Name: "X-Burger", Price: 25.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := clients.CreateClient(ctx, port.CreateClientRequest{Name: "Ana", Cpf: "529.982.247-25", Mail: "ana@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := func() error {
+		_, err := orders.CreateOrder(ctx, port.CreateOrderRequest{
+			Client:   string(client.Cpf),
+			Products: []port.ProductItem{{ID: burger.ID.String(), Quantity: 1}},
+		})
+		return err
+	}
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	for _, tc := range []struct {
+		name    string
+		id      string
+		request port.SetAvailabilityRequest
+		wantErr error
+	}{
+		{"InvalidID", "42", port.SetAvailabilityRequest{Status: domain.AvailabilityUnavailable}, domain.ErrValidation},
+		{"UnknownProduct", uuid.NewString(), port.SetAvailabilityRequest{Status: domain.AvailabilityUnavailable}, domain.ErrNotFound},
+		{"InvalidStatus", burger.ID.String(), port.SetAvailabilityRequest{Status: "sold out"}, domain.ErrValidation},
+		{"BackAtInThePast", burger.ID.String(), port.SetAvailabilityRequest{Status: domain.AvailabilityUnavailable, BackAt: &past}, domain.ErrValidation},
+		{"BackAtWhenAvailable", burger.ID.String(), port.SetAvailabilityRequest{Status: domain.AvailabilityAvailable, BackAt: &future}, domain.ErrValidation},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := products.SetAvailability(ctx, tc.id, tc.request); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("SetAvailability error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+	if err := order(); err != nil {
+		t.Fatalf("CreateOrder after failed availability changes: %v", err)
+	}
+
+	for _, status := range []string{domain.AvailabilityUnavailable, domain.AvailabilityDiscontinued} {
+		product, err := products.SetAvailability(ctx, burger.ID.String(), port.SetAvailabilityRequest{Status: status})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if product.Availability.Status != status {
+			t.Fatalf("availability = %+v, want %s", product.Availability, status)
+		}
+		if err := order(); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("CreateOrder of a product %s = %v, want a validation error", status, err)
+		}
+	}
+
+	product, err := products.SetAvailability(ctx, burger.ID.String(), port.SetAvailabilityRequest{Status: domain.AvailabilityUnavailable, BackAt: &future})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.Availability.BackAt == nil || !product.Availability.BackAt.Equal(future) {
+		t.Fatalf("back_at = %v, want %s", product.Availability.BackAt, future)
+	}
+
+	if _, err := products.SetAvailability(ctx, burger.ID.String(), port.SetAvailabilityRequest{Status: domain.AvailabilityAvailable}); err != nil {
+		t.Fatal(err)
+	}
+	if err := order(); err != nil {
+		t.Fatalf("CreateOrder once available again: %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"github.com/mfritschdotgo/techchallenge/internal/logging"
+)
+
+const (
+	webhookBatchSize  = 20
+	webhookLease      = time.Minute
+	webhookMinBackoff = 5 * time.Second
+	webhookMaxBackoff = time.Hour
+)
+
+// WebhookService manages partner subscriptions and delivers matching events to them.
+// It implements port.EventPublisher, so the outbox relay hands it every event.
+type WebhookService struct {
+	subscriptionRepo port.WebhookSubscriptionRepository
+	deliveryRepo     port.WebhookDeliveryRepository
+	sender           port.WebhookSender
+	running          atomic.Bool
+}
+
+var _ port.WebhookService = (*WebhookService)(nil)
+
+func NewWebhookService(subscriptionRepo port.WebhookSubscriptionRepository, deliveryRepo port.WebhookDeliveryRepository, sender port.WebhookSender) *WebhookService {
+	return &WebhookService{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		sender:           sender,
+	}
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, request port.CreateWebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	subscription, err := domain.NewWebhookSubscription(request.URL, request.EventTypes, request.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.subscriptionRepo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *WebhookService) ReplaceSubscription(ctx context.Context, id string, request port.CreateWebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	subscription, err := s.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.URL = request.URL
+	subscription.EventTypes = request.EventTypes
+	subscription.Secret = request.Secret
+	subscription.UpdatedAt = time.Now()
+
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.subscriptionRepo.ReplaceSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to replace webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (s *WebhookService) GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID format: %w", err)
+	}
+
+	subscription, err := s.subscriptionRepo.GetSubscriptionByID(ctx, uuidID.String())
+	if err != nil {
+		return nil, fmt.Errorf("webhook subscription not found: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context, page, size int) ([]domain.WebhookSubscription, int64, error) {
+	page, size = domain.NormalizePage(page, size)
+
+	return s.subscriptionRepo.GetSubscriptions(ctx, page, size)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id string) error {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid ID format: %w", err)
+	}
+
+	if err := s.subscriptionRepo.DeleteSubscription(ctx, uuidID.String()); err != nil {
+		return fmt.Errorf("webhook subscription not found or error deleting subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionID string, page, size int) ([]domain.WebhookDelivery, int64, error) {
+	if _, err := s.GetSubscriptionByID(ctx, subscriptionID); err != nil {
+		return nil, 0, err
+	}
+	page, size = domain.NormalizePage(page, size)
+
+	return s.deliveryRepo.GetDeliveries(ctx, subscriptionID, page, size)
+}
+
+func (s *WebhookService) GetDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID format: %w", err)
+	}
+
+	delivery, err := s.deliveryRepo.GetDeliveryByID(ctx, uuidID.String())
+	if err != nil {
+		return nil, fmt.Errorf("webhook delivery not found: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// Redeliver schedules a delivery to be attempted again by the next run of the worker.
+func (s *WebhookService) Redeliver(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	delivery, err := s.GetDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Redeliver()
+
+	if err := s.deliveryRepo.ReplaceDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to schedule webhook redelivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// Publish creates a pending delivery of event for every subscription interested in it.
+func (s *WebhookService) Publish(ctx context.Context, event domain.Event) error {
+	subscriptions, err := s.subscriptionRepo.GetSubscriptionsByEventType(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+
+	for i := range subscriptions {
+		delivery, err := domain.NewWebhookDelivery(&subscriptions[i], event)
+		if err != nil {
+			return err
+		}
+		if err := s.deliveryRepo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Running reports whether Run is looping, for readiness checks.
+func (s *WebhookService) Running() bool {
+	return s.running.Load()
+}
+
+// Run delivers due webhooks every interval until ctx is cancelled. A batch already
+// being delivered when that happens is finished first, so Run may return after ctx is done.
+func (s *WebhookService) Run(ctx context.Context, interval time.Duration) {
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.DeliverDue(context.WithoutCancel(ctx)); err != nil {
+			logging.FromContext(ctx).Error("webhook delivery failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue attempts one batch of due deliveries and returns how many were attempted.
+// A delivery that cannot be attempted is logged and left for a later batch; it does not
+// hold back the rest of the batch.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.deliveryRepo.ClaimDue(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		if err := s.attempt(ctx, &deliveries[i]); err != nil {
+			logging.FromContext(ctx).Error("webhook delivery could not be attempted",
+				"delivery_id", deliveries[i].ID, "subscription_id", deliveries[i].SubscriptionID, "error", err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (s *WebhookService) attempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	subscription, err := s.subscriptionRepo.GetSubscriptionByID(ctx, delivery.SubscriptionID.String())
+	if errors.Is(err, domain.ErrNotFound) {
+		// The subscription was deleted after the event was dispatched.
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.UpdatedAt = time.Now()
+		return s.deliveryRepo.ReplaceDelivery(ctx, delivery)
+	}
+	if err != nil {
+		// The delivery stays pending and is claimed again once its lease expires.
+		return fmt.Errorf("failed to load webhook subscription: %w", err)
+	}
+
+	start := time.Now()
+	code, err := s.sender.Send(ctx, subscription, delivery)
+	attempt := domain.WebhookAttempt{
+		At:           time.Now(),
+		ResponseCode: code,
+		DurationMs:   time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	} else if code < 200 || code > 299 {
+		attempt.Error = fmt.Sprintf("unexpected response status %d", code)
+	}
+
+	retryAt := time.Now().Add(backoff(len(delivery.Attempts), webhookMinBackoff, webhookMaxBackoff))
+	delivery.RecordAttempt(attempt, retryAt)
+	if attempt.Error != "" {
+		logging.FromContext(ctx).Warn("webhook attempt failed",
+			"delivery_id", delivery.ID, "subscription_id", delivery.SubscriptionID, "response_code", code, "error", attempt.Error)
+	}
+
+	if err := s.deliveryRepo.ReplaceDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
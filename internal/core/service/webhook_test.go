@@ -0,0 +1,366 @@
+package service_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mfritschdotgo/techchallenge/internal/adapter/event"
+	"github.com/mfritschdotgo/techchallenge/internal/core/domain"
+	"github.com/mfritschdotgo/techchallenge/internal/core/port"
+	"github.com/mfritschdotgo/techchallenge/internal/core/service"
+)
+
+const webhookSecret = "receiver-signing-secret"
+
+// receiver is a partner endpoint answering with the given statuses in turn, then 200.
+// It counts the requests whose signature does not verify.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests int
+	invalid  int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	want := event.Sign(webhookSecret, r.Header.Get(event.TimestampHeader), body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !hmac.Equal([]byte(r.Header.Get(event.SignatureHeader)), []byte(want)) {
+		rc.invalid++
+	}
+	status := http.StatusOK
+	if rc.requests < len(rc.statuses) {
+		status = rc.statuses[rc.requests]
+	}
+	rc.requests++
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) counts() (requests, invalid int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.requests, rc.invalid
+}
+
+// fakeSubscriptions keeps subscriptions in memory. GetSubscriptionByID fails with
+// lookupErrs[id] while it is set.
+type fakeSubscriptions struct {
+	subscriptions map[uuid.UUID]domain.WebhookSubscription
+	lookupErrs    map[string]error
+}
+
+func (r *fakeSubscriptions) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	r.subscriptions[subscription.ID] = *subscription
+	return subscription, nil
+}
+
+func (r *fakeSubscriptions) GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	if err := r.lookupErrs[id]; err != nil {
+		return nil, err
+	}
+	subscription, ok := r.subscriptions[uuid.MustParse(id)]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &subscription, nil
+}
+
+func (r *fakeSubscriptions) GetSubscriptions(ctx context.Context, page, limit int) ([]domain.WebhookSubscription, int64, error) {
+	var subscriptions []domain.WebhookSubscription
+	for _, subscription := range r.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, int64(len(subscriptions)), nil
+}
+
+func (r *fakeSubscriptions) GetSubscriptionsByEventType(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	for _, subscription := range r.subscriptions {
+		if subscription.Matches(eventType) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *fakeSubscriptions) ReplaceSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	r.subscriptions[subscription.ID] = *subscription
+	return subscription, nil
+}
+
+func (r *fakeSubscriptions) DeleteSubscription(ctx context.Context, id string) error {
+	if _, ok := r.subscriptions[uuid.MustParse(id)]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.subscriptions, uuid.MustParse(id))
+	return nil
+}
+
+// fakeDeliveries keeps deliveries in memory and claims them like the Mongo repository.
+type fakeDeliveries struct {
+	deliveries map[uuid.UUID]domain.WebhookDelivery
+}
+
+func (r *fakeDeliveries) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		r.deliveries[delivery.ID] = *delivery
+	}
+	return nil
+}
+
+func (r *fakeDeliveries) GetDeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	delivery, ok := r.deliveries[uuid.MustParse(id)]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &delivery, nil
+}
+
+func (r *fakeDeliveries) GetDeliveries(ctx context.Context, subscriptionID string, page, limit int) ([]domain.WebhookDelivery, int64, error) {
+	var deliveries []domain.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID.String() == subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, int64(len(deliveries)), nil
+}
+
+func (r *fakeDeliveries) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	now := time.Now()
+	for id, delivery := range r.deliveries {
+		if len(deliveries) == limit {
+			break
+		}
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.NextAttemptAt = now.Add(lease)
+		r.deliveries[id] = delivery
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *fakeDeliveries) ReplaceDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+type webhookFixture struct {
+	webhooks       *service.WebhookService
+	subscriptions  *fakeSubscriptions
+	deliveries     *fakeDeliveries
+	receiver       *receiver
+	subscriptionID string
+	deliveryID     string
+}
+
+// newWebhookFixture subscribes a receiver answering statuses to OrderCreated and
+// publishes one such event to it.
+func newWebhookFixture(t *testing.T, statuses ...int) *webhookFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := &webhookFixture{
+		subscriptions: &fakeSubscriptions{subscriptions: map[uuid.UUID]domain.WebhookSubscription{}},
+		deliveries:    &fakeDeliveries{deliveries: map[uuid.UUID]domain.WebhookDelivery{}},
+		receiver:      &receiver{statuses: statuses},
+	}
+	server := httptest.NewServer(f.receiver)
+	t.Cleanup(server.Close)
+	f.webhooks = service.NewWebhookService(f.subscriptions, f.deliveries, event.NewHTTPWebhookSender(server.Client()))
+
+	subscription, err := f.webhooks.CreateSubscription(ctx, port.CreateWebhookSubscriptionRequest{
+		URL:        server.URL,
+		EventTypes: []string{domain.EventOrderCreated},
+		Secret:     webhookSecret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.subscriptionID = subscription.ID.String()
+
+	orderID := uuid.New()
+	orderCreated, err := domain.NewEvent(domain.EventOrderCreated, orderID, map[string]string{"id": orderID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.webhooks.Publish(ctx, *orderCreated); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries, _, err := f.webhooks.GetDeliveries(ctx, f.subscriptionID, 1, 10)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("GetDeliveries = %d deliveries, %v, want 1", len(deliveries), err)
+	}
+	f.deliveryID = deliveries[0].ID.String()
+
+	return f
+}
+
+// deliverDue runs the worker once and checks how many deliveries it attempted.
+func (f *webhookFixture) deliverDue(t *testing.T, want int) {
+	t.Helper()
+	attempted, err := f.webhooks.DeliverDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempted != want {
+		t.Fatalf("DeliverDue attempted %d deliveries, want %d", attempted, want)
+	}
+}
+
+func (f *webhookFixture) delivery(t *testing.T) *domain.WebhookDelivery {
+	t.Helper()
+	delivery, err := f.webhooks.GetDeliveryByID(context.Background(), f.deliveryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return delivery
+}
+
+// fastForward makes the delivery due now, as if its retry time or lease had passed.
+func (f *webhookFixture) fastForward(t *testing.T) {
+	t.Helper()
+	delivery := f.delivery(t)
+	delivery.NextAttemptAt = time.Now()
+	if err := f.deliveries.ReplaceDelivery(context.Background(), delivery); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebhookDeliveryRetriesWithBackoff(t *testing.T) {
+	f := newWebhookFixture(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+
+	for i, want := range []time.Duration{5 * time.Second, 10 * time.Second} {
+		before := time.Now()
+		f.deliverDue(t, 1)
+
+		delivery := f.delivery(t)
+		if delivery.Status != domain.WebhookDeliveryPending || len(delivery.Attempts) != i+1 {
+			t.Fatalf("after failure %d: status %s with %d attempts", i+1, delivery.Status, len(delivery.Attempts))
+		}
+		if wait := delivery.NextAttemptAt.Sub(before); wait < want || wait > want+time.Second {
+			t.Errorf("after failure %d: retried in %s, want %s", i+1, wait, want)
+		}
+
+		f.deliverDue(t, 0)
+		f.fastForward(t)
+	}
+
+	f.deliverDue(t, 1)
+	delivery := f.delivery(t)
+	if delivery.Status != domain.WebhookDeliverySucceeded || len(delivery.Attempts) != 3 {
+		t.Fatalf("status %s with %d attempts, want succeeded with 3", delivery.Status, len(delivery.Attempts))
+	}
+	for i, want := range []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK} {
+		if got := delivery.Attempts[i].ResponseCode; got != want {
+			t.Errorf("attempt %d: response code %d, want %d", i+1, got, want)
+		}
+	}
+	if requests, invalid := f.receiver.counts(); requests != 3 || invalid != 0 {
+		t.Errorf("receiver got %d requests with %d invalid signatures, want 3 valid", requests, invalid)
+	}
+}
+
+func TestWebhookRedeliver(t *testing.T) {
+	f := newWebhookFixture(t)
+	f.deliverDue(t, 1)
+
+	delivery, err := f.webhooks.Redeliver(context.Background(), f.deliveryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != domain.WebhookDeliveryPending {
+		t.Fatalf("status %s after Redeliver, want %s", delivery.Status, domain.WebhookDeliveryPending)
+	}
+
+	f.deliverDue(t, 1)
+	delivery = f.delivery(t)
+	if delivery.Status != domain.WebhookDeliverySucceeded || len(delivery.Attempts) != 2 {
+		t.Fatalf("status %s with %d attempts, want succeeded with 2", delivery.Status, len(delivery.Attempts))
+	}
+	if requests, invalid := f.receiver.counts(); requests != 2 || invalid != 0 {
+		t.Errorf("receiver got %d requests with %d invalid signatures, want 2 valid", requests, invalid)
+	}
+}
+
+func TestWebhookDeliveryRetriesWhenSubscriptionLookupFails(t *testing.T) {
+	f := newWebhookFixture(t)
+	f.subscriptions.lookupErrs = map[string]error{f.subscriptionID: errors.New("connection reset by peer")}
+
+	f.deliverDue(t, 1)
+	if delivery := f.delivery(t); delivery.Status != domain.WebhookDeliveryPending || len(delivery.Attempts) != 0 {
+		t.Fatalf("status %s with %d attempts, want pending with none", delivery.Status, len(delivery.Attempts))
+	}
+
+	f.subscriptions.lookupErrs = nil
+	f.fastForward(t)
+	f.deliverDue(t, 1)
+	if delivery := f.delivery(t); delivery.Status != domain.WebhookDeliverySucceeded {
+		t.Fatalf("status %s, want %s", delivery.Status, domain.WebhookDeliverySucceeded)
+	}
+}
+
+func TestWebhookDeliveryFailureDoesNotHoldBackTheBatch(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+
+	other, err := f.webhooks.CreateSubscription(ctx, port.CreateWebhookSubscriptionRequest{
+		URL:        f.subscriptions.subscriptions[uuid.MustParse(f.subscriptionID)].URL,
+		EventTypes: []string{domain.EventOrderStatusChanged},
+		Secret:     webhookSecret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderID := uuid.New()
+	statusChanged, err := domain.NewEvent(domain.EventOrderStatusChanged, orderID, map[string]string{"id": orderID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.webhooks.Publish(ctx, *statusChanged); err != nil {
+		t.Fatal(err)
+	}
+	f.subscriptions.lookupErrs = map[string]error{f.subscriptionID: errors.New("connection reset by peer")}
+
+	f.deliverDue(t, 2)
+
+	deliveries, _, err := f.webhooks.GetDeliveries(ctx, other.ID.String(), 1, 10)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("GetDeliveries = %d deliveries, %v, want 1", len(deliveries), err)
+	}
+	if deliveries[0].Status != domain.WebhookDeliverySucceeded {
+		t.Errorf("other delivery status %s, want %s", deliveries[0].Status, domain.WebhookDeliverySucceeded)
+	}
+	if delivery := f.delivery(t); delivery.Status != domain.WebhookDeliveryPending {
+		t.Errorf("failed delivery status %s, want %s", delivery.Status, domain.WebhookDeliveryPending)
+	}
+}
+
+func TestWebhookDeliveryFailsWhenSubscriptionIsDeleted(t *testing.T) {
+	f := newWebhookFixture(t)
+	if err := f.webhooks.DeleteSubscription(context.Background(), f.subscriptionID); err != nil {
+		t.Fatal(err)
+	}
+
+	f.deliverDue(t, 1)
+	if delivery := f.delivery(t); delivery.Status != domain.WebhookDeliveryFailed {
+		t.Fatalf("status %s, want %s", delivery.Status, domain.WebhookDeliveryFailed)
+	}
+	if requests, _ := f.receiver.counts(); requests != 0 {
+		t.Errorf("receiver got %d requests, want none", requests)
+	}
+}
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose whole value is personal data. Orders refer
+// to their client by CPF, so client is redacted too.
+var sensitiveKeys = map[string]bool{
+	"cpf":    true,
+	"client": true,
+	"email":  true,
+	"mail":   true,
+}
+
+var (
+	cpfPattern   = regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// redact is the ReplaceAttr hook of the logger. Besides sensitive keys it scrubs CPFs and
+// email addresses from every string and error, such as the message, a request path like
+// /clients/{cpf} or a wrapped validation error.
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, scrub(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, scrub(err.Error()))
+		}
+	}
+	return a
+}
+
+func scrub(s string) string {
+	s = cpfPattern.ReplaceAllString(s, redacted)
+	return emailPattern.ReplaceAllString(s, redacted)
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	logger.Info("client 529.982.247-25 signed up",
+		"cpf", "52998224725",
+		"mail", "ana@example.com",
+		"path", "/clients/52998224725",
+		"error", fmt.Errorf("sending to ana@example.com: %w", errors.New("timeout")),
+		"order_id", "6f1c2a34-5b6d-4e7f-8a9b-123456789012",
+	)
+
+	output := buf.String()
+	for _, secret := range []string{"529.982.247-25", "52998224725", "ana@example.com"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("log line leaks %q: %s", secret, output)
+		}
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log line is not JSON: %v", err)
+	}
+	if line["order_id"] != "6f1c2a34-5b6d-4e7f-8a9b-123456789012" {
+		t.Errorf("order_id = %v, want it untouched", line["order_id"])
+	}
+	if line["error"] != "sending to [REDACTED]: timeout" {
+		t.Errorf("error = %v", line["error"])
+	}
+}
+
+func TestNewRejectsUnknownLevel(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "verbose"); err == nil {
+		t.Fatal("New accepted level verbose")
+	}
+}